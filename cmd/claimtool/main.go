@@ -0,0 +1,268 @@
+/*
+Claimtool decodes and inspects claim values.
+
+Usage:
+
+	claimtool decode [-json] [-network name] <value>
+	claimtool strip [-network name] <value>
+	claimtool reencode [-network name] <value>
+	claimtool verify -channel <value> -channel-id <id> -input <txid or address> [-network name] <value>
+	claimtool diff [-network name] <old value> <new value>
+	claimtool lint [-profile odysee|spee.ch|minimal] [-name claim name] [-network name] <value>
+
+Values can be hex or base64, and "-" reads the value from stdin. The network is lbrycrd_main unless set.
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/schema/stake"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+const usage = `usage:
+	claimtool decode [-json] [-network name] <value>
+	claimtool strip [-network name] <value>
+	claimtool reencode [-network name] <value>
+	claimtool verify -channel <value> -channel-id <id> -input <txid or address> [-network name] <value>
+	claimtool diff [-network name] <old value> <new value>
+	claimtool lint [-profile odysee|spee.ch|minimal] [-name claim name] [-network name] <value>
+
+values can be hex or base64, and "-" reads the value from stdin`
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.Err(usage)
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	network := flags.String("network", "lbrycrd_main", "blockchain the claim is from")
+	asJSON := flags.Bool("json", false, "print the claim as json")
+	channel := flags.String("channel", "", "value of the channel claim that signed the claim")
+	channelID := flags.String("channel-id", "", "claim id of the signing channel")
+	profile := flags.String("profile", "minimal", "validation profile to lint against")
+	name := flags.String("name", "", "claim name to lint")
+	input := flags.String("input", "", "first input txid of the claim transaction, or the claim address for legacy claims")
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return errors.Err("%s\n%s", err, usage)
+	}
+
+	decode := func(value string) (*stake.StakeHelper, []byte, error) {
+		raw, err := readValue(value, stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+		helper, err := stake.DecodeClaimBytes(raw, *network)
+		return helper, raw, err
+	}
+
+	switch args[0] {
+	case "decode", "strip", "reencode", "verify", "lint":
+		if flags.NArg() != 1 {
+			return errors.Err("%s takes one value\n%s", args[0], usage)
+		}
+		helper, raw, err := decode(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		switch args[0] {
+		case "decode":
+			return printClaim(stdout, helper, *asJSON)
+		case "strip":
+			return strip(stdout, helper)
+		case "reencode":
+			return reencode(stdout, helper, raw)
+		case "lint":
+			return lint(stdout, helper, *name, *profile)
+		default:
+			if *channel == "" || *channelID == "" || *input == "" {
+				return errors.Err("verify needs -channel, -channel-id and -input\n%s", usage)
+			}
+			certificate, _, err := decode(*channel)
+			if err != nil {
+				return errors.Prefix("channel", err)
+			}
+			return verify(stdout, helper, certificate, *channelID, *input, *network)
+		}
+
+	case "diff":
+		if flags.NArg() != 2 {
+			return errors.Err("diff takes two values\n%s", usage)
+		}
+		previous, _, err := decode(flags.Arg(0))
+		if err != nil {
+			return errors.Prefix("old value", err)
+		}
+		desired, _, err := decode(flags.Arg(1))
+		if err != nil {
+			return errors.Prefix("new value", err)
+		}
+		return diff(stdout, previous, desired)
+	}
+	return errors.Err("unknown command %q\n%s", args[0], usage)
+}
+
+// readValue decodes a hex or base64 value, reading it from stdin if it is "-"
+func readValue(value string, stdin io.Reader) ([]byte, error) {
+	if value == "-" {
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return nil, errors.Err(err)
+		}
+		value = string(b)
+	}
+	value = strings.TrimSpace(value)
+	if raw, err := hex.DecodeString(value); err == nil {
+		return raw, nil
+	}
+	if raw, err := base64.StdEncoding.DecodeString(value); err == nil {
+		return raw, nil
+	}
+	if raw, err := base64.URLEncoding.DecodeString(value); err == nil {
+		return raw, nil
+	}
+	return nil, errors.Err("value is neither hex nor base64")
+}
+
+func printClaim(w io.Writer, helper *stake.StakeHelper, asJSON bool) error {
+	if asJSON {
+		m := jsonpb.Marshaler{Indent: "  "}
+		var err error
+		if helper.LegacyClaim != nil {
+			err = m.Marshal(w, helper.LegacyClaim)
+		} else {
+			err = m.Marshal(w, helper.Claim)
+		}
+		if err != nil {
+			return errors.Err(err)
+		}
+		_, err = fmt.Fprintln(w)
+		return errors.Err(err)
+	}
+
+	format := "v2"
+	if helper.LegacyClaim != nil {
+		format = "legacy protobuf"
+	} else if helper.Payload == nil {
+		format = "legacy json"
+	}
+	fmt.Fprintf(w, "format: %s\n", format)
+	if helper.Signature != nil {
+		fmt.Fprintf(w, "signed by: %s\n", signingChannelID(helper))
+		fmt.Fprintf(w, "signature: %s\n", hex.EncodeToString(helper.Signature))
+	} else {
+		fmt.Fprintln(w, "signed by: nobody")
+	}
+	fmt.Fprintln(w)
+	if helper.LegacyClaim != nil {
+		return proto.MarshalText(w, helper.LegacyClaim)
+	}
+	return proto.MarshalText(w, helper.Claim)
+}
+
+// signingChannelID returns the channel claim id in the usual byte order. Legacy claims store it that way, newer
+// claims store it reversed.
+func signingChannelID(helper *stake.StakeHelper) string {
+	id := append([]byte(nil), helper.ClaimID...)
+	if helper.LegacyClaim == nil {
+		for i, j := 0, len(id)-1; i < j; i, j = i+1, j-1 {
+			id[i], id[j] = id[j], id[i]
+		}
+	}
+	return hex.EncodeToString(id)
+}
+
+func strip(w io.Writer, helper *stake.StakeHelper) error {
+	value, err := helper.UnsignedValue()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, hex.EncodeToString(value))
+	return errors.Err(err)
+}
+
+func reencode(w io.Writer, helper *stake.StakeHelper, raw []byte) error {
+	var value []byte
+	var err error
+	if helper.LegacyClaim != nil {
+		// legacy values have no version byte, and their signature is part of the protobuf
+		value, err = proto.Marshal(helper.LegacyClaim)
+		err = errors.Err(err)
+	} else {
+		value, err = helper.CompileValue()
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, hex.EncodeToString(value))
+	if err == nil && !bytes.Equal(value, raw) {
+		_, err = fmt.Fprintln(w, "note: the re-encoded value differs from the input")
+	}
+	return errors.Err(err)
+}
+
+func verify(w io.Writer, helper, certificate *stake.StakeHelper, channelID, input, network string) error {
+	if helper.Signature == nil {
+		return errors.Err("claim is not signed")
+	}
+	valid, err := helper.ValidateClaimSignature(certificate, input, channelID, network)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.Err("signature is not valid")
+	}
+	_, err = fmt.Fprintln(w, "signature is valid")
+	return errors.Err(err)
+}
+
+func diff(w io.Writer, previous, desired *stake.StakeHelper) error {
+	d, err := stake.Diff(previous, desired)
+	if err != nil {
+		return err
+	}
+	if !d.NeedsUpdate() {
+		_, err = fmt.Fprintln(w, "no changes")
+		return errors.Err(err)
+	}
+	fmt.Fprintf(w, "changed: %s\n\n", strings.Join(d.Fields, ", "))
+	return proto.MarshalText(w, d.Changes)
+}
+
+func lint(w io.Writer, helper *stake.StakeHelper, name, profileName string) error {
+	profile, err := stake.LookupProfile(profileName)
+	if err != nil {
+		return err
+	}
+	problems := helper.Lint(name, profile)
+	for _, problem := range problems {
+		fmt.Fprintln(w, problem)
+	}
+	if len(problems) > 0 {
+		return errors.Err("%d problems found", len(problems))
+	}
+	_, err = fmt.Fprintln(w, "no problems found")
+	return errors.Err(err)
+}
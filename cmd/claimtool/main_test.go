@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+const (
+	streamHex         = "000aa4010a8a010a30f1303989f58396694b0c5982c97f7e9d9435841d92aa13f4b80f671c27110c469babc4fbf4bd764155eaac089cfc49e8121454554d205045204d45524e45204c41472e6d703418cad0c8012209766964656f2f6d70343230c2c9389731e2a9568f66c78d703736a8c341015ada2e46f5dcc87aa6f08ab17c02df2121d9f6ef74055827a29dfc75801a044e6f6e6532040803180a5a0908b001109001188102421054554d205045204d45524e45204c41474a0944657369206c6f636b62020801"
+	legacySignedHex   = "080110011ad7010801128f01080410011a0c47616d65206f66206c696665221047616d65206f66206c696665206769662a0b4a6f686e20436f6e776179322e437265617469766520436f6d6d6f6e73204174747269627574696f6e20342e3020496e7465726e6174696f6e616c38004224080110011a195569c917f18bf5d2d67f1346aa467b218ba90cdbf2795676da250000803f4a0052005a001a41080110011a30b6adf6e2a62950407ea9fb045a96127b67d39088678d2f738c359894c88d95698075ee6203533d3c204330713aa7acaf2209696d6167652f6769662a5c080110031a40c73fe1be4f1743c2996102eec6ce0509e03744ab940c97d19ddb3b25596206367ab1a3d2583b16c04d2717eeb983ae8f84fee2a46621ffa5c4726b30174c6ff82214251305ca93d4dbedb50dceb282ebcb7b07b7ac65"
+	legacyUnsignedHex = "080110011ad7010801128f01080410011a0c47616d65206f66206c696665221047616d65206f66206c696665206769662a0b4a6f686e20436f6e776179322e437265617469766520436f6d6d6f6e73204174747269627574696f6e20342e3020496e7465726e6174696f6e616c38004224080110011a195569c917f18bf5d2d67f1346aa467b218ba90cdbf2795676da250000803f4a0052005a001a41080110011a30b6adf6e2a62950407ea9fb045a96127b67d39088678d2f738c359894c88d95698075ee6203533d3c204330713aa7acaf2209696d6167652f676966"
+)
+
+func runTool(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestDecode(t *testing.T) {
+	out := runTool(t, "", "decode", streamHex)
+	if !strings.Contains(out, "format: v2") || !strings.Contains(out, "TUM PE MERNE LAG") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	raw, _ := hex.DecodeString(streamHex)
+	out = runTool(t, base64.StdEncoding.EncodeToString(raw), "decode", "-json", "-")
+	if !strings.Contains(out, `"title": "TUM PE MERNE LAG"`) {
+		t.Errorf("unexpected json output:\n%s", out)
+	}
+
+	out = runTool(t, "", "decode", legacySignedHex)
+	if !strings.Contains(out, "format: legacy protobuf") || !strings.Contains(out, "signed by: 251305ca93d4dbedb50dceb282ebcb7b07b7ac65") {
+		t.Errorf("unexpected legacy output:\n%s", out)
+	}
+}
+
+func TestStrip(t *testing.T) {
+	out := runTool(t, "", "strip", legacySignedHex)
+	if strings.TrimSpace(out) != legacyUnsignedHex {
+		t.Errorf("expected the unsigned value, got %s", out)
+	}
+}
+
+func TestReencode(t *testing.T) {
+	out := runTool(t, "", "reencode", legacyUnsignedHex)
+	if strings.TrimSpace(out) != legacyUnsignedHex {
+		t.Errorf("expected the value to round trip, got %s", out)
+	}
+
+	// the fields are written in a different order than the value was published with
+	out = runTool(t, "", "reencode", streamHex)
+	lines := strings.Split(out, "\n")
+	if !strings.Contains(out, "differs from the input") {
+		t.Errorf("expected a note about the difference, got %s", out)
+	}
+	if decoded := runTool(t, "", "decode", lines[0]); !strings.Contains(decoded, "TUM PE MERNE LAG") {
+		t.Errorf("re-encoded value decodes to\n%s", decoded)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	out := runTool(t, "", "diff", legacySignedHex, legacyUnsignedHex)
+	if strings.TrimSpace(out) != "no changes" {
+		t.Errorf("a signature alone should not count as a change, got %s", out)
+	}
+	out = runTool(t, "", "diff", legacyUnsignedHex, streamHex)
+	if !strings.HasPrefix(out, "changed: title") {
+		t.Errorf("unexpected diff:\n%s", out)
+	}
+}
+
+func TestLint(t *testing.T) {
+	out := runTool(t, "", "lint", "-profile", "odysee", "-name", "tum-pe", streamHex)
+	if strings.TrimSpace(out) != "no problems found" {
+		t.Errorf("unexpected output %s", out)
+	}
+	var buf bytes.Buffer
+	err := run([]string{"lint", "-profile", "spee.ch", "-name", strings.Repeat("x", 100), streamHex}, strings.NewReader(""), &buf)
+	if err == nil || !strings.HasPrefix(buf.String(), "name: ") {
+		t.Errorf("expected a problem with the name, got %q, %v", buf.String(), err)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"explode"},
+		{"decode"},
+		{"decode", "not a value!"},
+		{"verify", legacySignedHex},
+		{"diff", streamHex},
+		{"lint", "-profile", "myspace", streamHex},
+	} {
+		if err := run(args, strings.NewReader(""), &bytes.Buffer{}); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
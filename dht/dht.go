@@ -96,11 +96,7 @@ func (dht *DHT) Start() error {
 	}()
 
 	if dht.conf.RPCPort > 0 {
-		dht.grp.Add(1)
-		go func() {
-			dht.runRPCServer(dht.conf.RPCPort)
-			dht.grp.Done()
-		}()
+		dht.runRPCServer(dht.conf.RPCPort)
 	}
 
 	return nil
@@ -150,6 +146,9 @@ func (dht *DHT) WaitUntilJoined() {
 func (dht *DHT) Shutdown() {
 	log.Debugf("[%s] DHT shutting down", dht.node.id.HexShort())
 	dht.grp.StopAndWait()
+	for _, err := range dht.grp.Errors() {
+		log.Error(err)
+	}
 	dht.node.Shutdown()
 	log.Debugf("[%s] DHT stopped", dht.node.id.HexShort())
 }
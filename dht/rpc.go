@@ -1,11 +1,9 @@
 package dht
 
 import (
-	"context"
 	"net"
 	"net/http"
 	"strconv"
-	"sync"
 
 	"github.com/lbryio/lbry.go/v2/dht/bits"
 	"github.com/lbryio/lbry.go/v2/extras/errors"
@@ -166,22 +164,10 @@ func (dht *DHT) runRPCServer(port int) {
 	handler.Handle("/", s)
 	server := &http.Server{Addr: addr, Handler: handler}
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		log.Printf("RPC server listening on %s", addr)
-		err := server.ListenAndServe()
-		if err != nil && err != http.ErrServerClosed {
-			log.Error(err)
-		}
-	}()
-
-	<-dht.grp.Ch()
-	err = server.Shutdown(context.Background())
+	err = dht.grp.ListenAndServe(server)
 	if err != nil {
-		log.Error(errors.Prefix("shutting down rpc service", err))
+		log.Error(errors.Prefix("starting rpc service", err))
 		return
 	}
-	wg.Wait()
+	log.Printf("RPC server listening on %s", addr)
 }
@@ -0,0 +1,170 @@
+package jsonrpc
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// Component is a part of the daemon that can be turned off with the components_to_skip setting
+type Component string
+
+const (
+	ComponentWallet        = Component("wallet")
+	ComponentDHT           = Component("dht")
+	ComponentBlobManager   = Component("blob_manager")
+	ComponentFileManager   = Component("file_manager")
+	ComponentHashAnnouncer = Component("hash_announcer")
+)
+
+// ErrComponentDisabled is returned, wrapped, by calls that need a component the daemon runs without
+var ErrComponentDisabled = errors.Base("daemon component is disabled")
+
+// Capabilities describes which components a daemon runs
+type Capabilities struct {
+	skipped map[Component]bool
+}
+
+// Has returns true if the daemon runs the component
+func (c *Capabilities) Has(component Component) bool {
+	return !c.skipped[component]
+}
+
+// Skipped returns the components the daemon runs without
+func (c *Capabilities) Skipped() []Component {
+	skipped := make([]Component, 0, len(c.skipped))
+	for component := range c.skipped {
+		skipped = append(skipped, component)
+	}
+	return skipped
+}
+
+// capabilityCache holds the capabilities of each daemon a client talks to, by address. It is shared by a client and
+// its copies.
+type capabilityCache struct {
+	mu      sync.Mutex
+	entries map[string]*capabilityEntry
+}
+
+// capabilityEntry is the result of fetching a daemon's capabilities. Its fields are guarded by capabilityCache.mu.
+type capabilityEntry struct {
+	ready   chan struct{} // closed once the fetch is done
+	done    bool
+	caps    *Capabilities
+	err     error
+	fetched time.Time
+}
+
+// capabilityRetryInterval is how long a daemon whose capabilities could not be fetched is assumed to run every
+// component, so calls to a daemon that is down or starting don't each ask for its status first
+var capabilityRetryInterval = 30 * time.Second
+
+func newCapabilityCache() *capabilityCache {
+	return &capabilityCache{entries: make(map[string]*capabilityEntry)}
+}
+
+// forget drops the capabilities of the daemon at address, e.g. because it could not be reached and may come back with
+// a different configuration. Failed fetches are kept until they are due to be retried.
+func (c *capabilityCache) forget(address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[address]; ok && entry.done && entry.err == nil {
+		delete(c.entries, address)
+	}
+}
+
+// methodComponents maps method prefixes to the component they need. A full method name takes precedence over its prefix.
+var methodComponents = map[string]Component{
+	"account_":      ComponentWallet,
+	"address_":      ComponentWallet,
+	"channel_":      ComponentWallet,
+	"claim_":        ComponentWallet,
+	"collection_":   ComponentWallet,
+	"purchase_":     ComponentWallet,
+	"stream_":       ComponentWallet,
+	"support_":      ComponentWallet,
+	"sync_":         ComponentWallet,
+	"transaction_":  ComponentWallet,
+	"txo_":          ComponentWallet,
+	"utxo_":         ComponentWallet,
+	"wallet_":       ComponentWallet,
+	"resolve":       ComponentWallet,
+	"publish":       ComponentWallet,
+	"get":           ComponentFileManager,
+	"file_":         ComponentFileManager,
+	"peer_":         ComponentDHT,
+	"blob_announce": ComponentHashAnnouncer,
+}
+
+// RequiredComponent returns the component a daemon method needs, if any
+func RequiredComponent(method string) (Component, bool) {
+	if component, ok := methodComponents[method]; ok {
+		return component, true
+	}
+	if i := strings.Index(method, "_"); i > 0 {
+		component, ok := methodComponents[method[:i+1]]
+		return component, ok
+	}
+	return "", false
+}
+
+// Capabilities returns the components run by the daemon calls go to. They are fetched from its status once and cached
+// until the daemon can't be reached. A fetch that failed is not tried again for a while.
+func (d *Client) Capabilities() (*Capabilities, error) {
+	address := d.activeAddress()
+	cache := d.capabilities
+	cache.mu.Lock()
+	entry := cache.entries[address]
+	if entry == nil || (entry.done && entry.err != nil && time.Since(entry.fetched) >= capabilityRetryInterval) {
+		entry = &capabilityEntry{ready: make(chan struct{})}
+		cache.entries[address] = entry
+		cache.mu.Unlock()
+		caps, err := d.fetchCapabilities(address)
+
+		cache.mu.Lock()
+		entry.caps, entry.err, entry.done = caps, err, true
+		entry.fetched = time.Now()
+		cache.mu.Unlock()
+		close(entry.ready)
+		return caps, err
+	}
+	cache.mu.Unlock()
+
+	// another call is fetching them, or has already
+	select {
+	case <-entry.ready:
+	case <-d.ctx.Done():
+		return nil, errors.Err(d.ctx.Err())
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return entry.caps, entry.err
+}
+
+func (d *Client) fetchCapabilities(address string) (*Capabilities, error) {
+	status, err := d.statusAt(d.ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	caps := &Capabilities{skipped: make(map[Component]bool)}
+	for _, component := range status.SkippedComponents {
+		caps.skipped[Component(component)] = true
+	}
+	return caps, nil
+}
+
+// checkCapabilities returns ErrComponentDisabled if the method needs a component the daemon runs without. If the
+// capabilities can't be determined, the call is let through and the daemon gets to decide.
+func (d *Client) checkCapabilities(method string) error {
+	component, ok := RequiredComponent(method)
+	if !ok {
+		return nil
+	}
+	caps, err := d.Capabilities()
+	if err != nil || caps.Has(component) {
+		return nil
+	}
+	return errors.Prefix(method+" needs the "+string(component)+" component", ErrComponentDisabled)
+}
@@ -0,0 +1,204 @@
+package jsonrpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+func TestRequiredComponent(t *testing.T) {
+	tests := map[string]Component{
+		"account_balance": ComponentWallet,
+		"resolve":         ComponentWallet,
+		"file_list":       ComponentFileManager,
+		"get":             ComponentFileManager,
+		"peer_list":       ComponentDHT,
+	}
+	for method, expected := range tests {
+		component, ok := RequiredComponent(method)
+		if !ok || component != expected {
+			t.Errorf("%s: expected %s, got %s", method, expected, component)
+		}
+	}
+	if _, ok := RequiredComponent("status"); ok {
+		t.Error("status should not need any component")
+	}
+}
+
+func TestClient_Capabilities(t *testing.T) {
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"status": func(params map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"skipped_components": []string{"wallet", "dht"}}, nil
+		},
+		"file_list": func(params map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"items": []interface{}{}, "page": 1, "page_size": 1, "total_pages": 1}, nil
+		},
+	})
+
+	d := NewClient(daemon.URL)
+	caps, err := d.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.Has(ComponentWallet) || caps.Has(ComponentDHT) || !caps.Has(ComponentFileManager) {
+		t.Errorf("unexpected capabilities %v", caps.Skipped())
+	}
+
+	_, err = d.WithTimeout(0).AccountBalance(nil)
+	if !errors.Is(err, ErrComponentDisabled) {
+		t.Errorf("expected a disabled component error, got %v", err)
+	}
+	if daemon.Calls("account_balance") != 0 {
+		t.Error("calls that need a disabled component should not reach the daemon")
+	}
+
+	_, err = d.FileList(1, 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if daemon.Calls("status") != 1 {
+		t.Errorf("expected status to be fetched once, got %d", daemon.Calls("status"))
+	}
+}
+
+func TestClient_CapabilitiesConcurrent(t *testing.T) {
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"status": func(params map[string]interface{}) (interface{}, error) {
+			time.Sleep(50 * time.Millisecond)
+			return map[string]interface{}{"skipped_components": []string{"dht"}}, nil
+		},
+	})
+
+	d := NewClient(daemon.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			caps, err := d.Capabilities()
+			if err != nil || caps.Has(ComponentDHT) {
+				t.Errorf("unexpected capabilities %v, %v", caps, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if daemon.Calls("status") != 1 {
+		t.Errorf("expected status to be fetched once, got %d", daemon.Calls("status"))
+	}
+}
+
+func TestClient_CapabilitiesFailed(t *testing.T) {
+	defer func(interval time.Duration) { capabilityRetryInterval = interval }(capabilityRetryInterval)
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"status": func(params map[string]interface{}) (interface{}, error) {
+			return nil, errors.Err("starting")
+		},
+		"account_balance": func(params map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"available": "1.0"}, nil
+		},
+	})
+
+	d := NewClient(daemon.URL)
+	for i := 0; i < 3; i++ {
+		_, err := d.AccountBalance(nil)
+		if err != nil {
+			t.Fatalf("calls should go through when the capabilities are unknown, got %v", err)
+		}
+	}
+	if daemon.Calls("status") != 1 {
+		t.Errorf("expected a failed status to be remembered, got %d calls", daemon.Calls("status"))
+	}
+
+	capabilityRetryInterval = 0
+	_, _ = d.Capabilities()
+	_, _ = d.Capabilities()
+	if daemon.Calls("status") != 3 {
+		t.Errorf("expected status to be tried again, got %d calls", daemon.Calls("status"))
+	}
+}
+
+func TestClient_CapabilitiesUnreachable(t *testing.T) {
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"status": func(params map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{}, nil
+		},
+		"version": func(params map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"lbrynet_version": "0.1"}, nil
+		},
+	})
+	// drops the connection while down, as a daemon that restarts does
+	var down int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			panic(http.ErrAbortHandler)
+		}
+		response, err := http.Post(daemon.URL, "application/json", r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer response.Body.Close()
+		_, _ = io.Copy(w, response.Body)
+	}))
+	defer proxy.Close()
+
+	d := NewClient(proxy.URL)
+	_, err := d.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&down, 1)
+	_, err = d.Version()
+	if err == nil {
+		t.Fatal("expected the call to fail while the daemon is down")
+	}
+	atomic.StoreInt32(&down, 0)
+	_, err = d.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if daemon.Calls("status") != 2 {
+		t.Errorf("expected the capabilities to be fetched again after the daemon was unreachable, got %d", daemon.Calls("status"))
+	}
+}
+
+func TestClient_CapabilitiesPerEndpoint(t *testing.T) {
+	status := func(skipped ...string) fakeHandler {
+		return func(params map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"is_running": true, "skipped_components": skipped}, nil
+		}
+	}
+	version := func(params map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"lbrynet_version": "0.1"}, nil
+	}
+	primary := newFakeDaemon(t, map[string]fakeHandler{"status": status(), "version": version})
+	secondary := newFakeDaemon(t, map[string]fakeHandler{"status": status("wallet"), "version": version})
+
+	d := NewFailoverClient([]string{primary.URL, secondary.URL}, FailoverOptions{RecheckInterval: time.Hour})
+	caps, err := d.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !caps.Has(ComponentWallet) {
+		t.Error("the primary runs the wallet")
+	}
+
+	primary.Close()
+	_, err = d.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps, err = d.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.Has(ComponentWallet) {
+		t.Error("expected the capabilities of the daemon calls fail over to")
+	}
+}
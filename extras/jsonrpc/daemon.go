@@ -1,6 +1,8 @@
 package jsonrpc
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,6 +14,8 @@ import (
 
 	"github.com/fatih/structs"
 	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/extras/util/retry"
+	"github.com/lbryio/lbry.go/v2/schema/stake"
 	"github.com/mitchellh/mapstructure"
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
@@ -21,34 +25,50 @@ import (
 const DefaultPort = 5279
 
 type Client struct {
-	conn    jsonrpc.RPCClient
-	address string
+	httpClient   *http.Client
+	ctx          context.Context
+	timeouts     map[MethodClass]time.Duration
+	capabilities *capabilityCache
+	endpoints    *endpointPool
+	address      string
+	// allowSensitive lets the client export seeds and private keys, see AllowSensitive
+	allowSensitive bool
+	// flights holds the read calls in flight when deduplication is on, see SetDeduplicateReads
+	flights *flightGroup
 }
 
 func NewClient(address string) *Client {
-	d := Client{}
+	d := Client{
+		httpClient:   &http.Client{},
+		ctx:          context.Background(),
+		timeouts:     make(map[MethodClass]time.Duration),
+		capabilities: newCapabilityCache(),
+	}
 
 	if address == "" {
 		address = "http://localhost:" + strconv.Itoa(DefaultPort)
 	}
 
-	d.conn = jsonrpc.NewClient(address)
 	d.address = address
+	for class, timeout := range DefaultTimeouts {
+		d.SetTimeout(class, timeout)
+	}
 
 	return &d
 }
 
 func NewClientAndWait(address string) *Client {
 	d := NewClient(address)
-	for {
+	_ = retry.Do(context.Background(), daemonStartPolicy, func() error {
 		_, err := d.AccountBalance(nil)
-		if err == nil {
-			return d
-		}
-		time.Sleep(5 * time.Second)
-	}
+		return err
+	})
+	return d
 }
 
+// daemonStartPolicy polls a starting daemon every 5 seconds until it answers
+var daemonStartPolicy = retry.Policy{MinInterval: 5 * time.Second, Multiplier: 1}
+
 func Decode(data interface{}, targetStruct interface{}) error {
 	config := &mapstructure.DecoderConfig{
 		Metadata: nil,
@@ -93,6 +113,10 @@ func decodeNumber(data interface{}) (decimal.Decimal, error) {
 func debugParams(params map[string]interface{}) string {
 	var s []string
 	for k, v := range params {
+		if sensitiveParams[k] {
+			s = append(s, k+"=[redacted]")
+			continue
+		}
 		r := reflect.ValueOf(v)
 		if r.Kind() == reflect.Ptr {
 			if r.IsNil() {
@@ -106,20 +130,135 @@ func debugParams(params map[string]interface{}) string {
 	return strings.Join(s, " ")
 }
 
+// WithContext returns a copy of the client whose calls are canceled when ctx is done. The per method class timeouts
+// still apply on top of any deadline ctx has.
+func (d *Client) WithContext(ctx context.Context) *Client {
+	c := d.clone()
+	c.ctx = ctx
+	return c
+}
+
+// Context returns the context the client's calls run under
+func (d *Client) Context() context.Context {
+	return d.ctx
+}
+
+func (d *Client) clone() *Client {
+	c := *d
+	c.timeouts = make(map[MethodClass]time.Duration, len(d.timeouts))
+	for class, timeout := range d.timeouts {
+		c.timeouts[class] = timeout
+	}
+	return &c
+}
+
 func (d *Client) CallNoDecode(command string, params map[string]interface{}) (interface{}, error) {
 	log.Debugln("jsonrpc: " + command + " " + debugParams(params))
-	r, err := d.conn.Call(command, params)
+
+	if err := d.checkSensitive(command, params); err != nil {
+		return nil, err
+	}
+	if err := d.checkCapabilities(command); err != nil {
+		return nil, err
+	}
+
+	ctx := d.ctx
+	if timeout := d.timeouts[ClassOf(command)]; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	r, err := d.doDeduplicated(ctx, jsonrpc.NewRequest(command, params))
 	if err != nil {
-		return nil, errors.Wrap(err, 0)
+		return nil, err
 	}
 
 	if r.Error != nil {
-		return nil, errors.Err("Error in daemon: " + r.Error.Message)
+		return nil, errors.Err(&DaemonError{Code: r.Error.Code, Message: r.Error.Message})
 	}
 
 	return r.Result, nil
 }
 
+// DaemonError is an error the daemon answered a call with, as opposed to an error reaching the daemon or reading its
+// answer. Sending the same call again gets the same error.
+type DaemonError struct {
+	Code    int
+	Message string
+}
+
+func (e *DaemonError) Error() string {
+	return "Error in daemon: " + e.Message
+}
+
+// IsDaemonError returns true if err is an error the daemon answered with
+func IsDaemonError(err error) bool {
+	_, ok := errors.Unwrap(err).(*DaemonError)
+	return ok
+}
+
+func (d *Client) do(ctx context.Context, request *jsonrpc.RPCRequest) (*jsonrpc.RPCResponse, error) {
+	if d.endpoints != nil {
+		return d.doFailover(ctx, request)
+	}
+	return d.post(ctx, d.address, request)
+}
+
+// post sends the request to the daemon at address
+func (d *Client) post(ctx context.Context, address string, request *jsonrpc.RPCRequest) (*jsonrpc.RPCResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Accept", "application/json")
+
+	httpResponse, err := d.httpClient.Do(httpRequest)
+	if err != nil {
+		if ctx.Err() == nil {
+			// the daemon may have restarted with other components
+			d.capabilities.forget(address)
+		}
+		return nil, errors.Err("rpc call %s() on %s: %w", request.Method, address, err)
+	}
+	defer httpResponse.Body.Close()
+
+	var response *jsonrpc.RPCResponse
+	decoder := json.NewDecoder(httpResponse.Body)
+	decoder.UseNumber()
+	err = decoder.Decode(&response)
+	if err != nil {
+		return nil, errors.Err("rpc call %s() on %s status code: %d. could not decode body to rpc response: %v", request.Method, address, httpResponse.StatusCode, err)
+	}
+	if response == nil {
+		return nil, errors.Err("rpc call %s() on %s status code: %d. rpc response missing", request.Method, address, httpResponse.StatusCode)
+	}
+	return response, nil
+}
+
+// statusAt calls status on the daemon at address, under the read timeout
+func (d *Client) statusAt(ctx context.Context, address string) (*StatusResponse, error) {
+	if timeout := d.timeouts[MethodClassRead]; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	response, err := d.post(ctx, address, jsonrpc.NewRequest("status"))
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, errors.Err(&DaemonError{Code: response.Error.Code, Message: response.Error.Message})
+	}
+	status := new(StatusResponse)
+	return status, Decode(response.Result, status)
+}
+
 func (d *Client) Call(response interface{}, command string, params map[string]interface{}) error {
 	result, err := d.CallNoDecode(command, params)
 	if err != nil {
@@ -128,10 +267,11 @@ func (d *Client) Call(response interface{}, command string, params map[string]in
 	return Decode(result, response)
 }
 
+// SetRPCTimeout sets the same timeout for every method class. Use SetTimeout to configure each class separately.
 func (d *Client) SetRPCTimeout(timeout time.Duration) {
-	d.conn = jsonrpc.NewClientWithOpts(d.address, &jsonrpc.RPCClientOpts{
-		HTTPClient: &http.Client{Timeout: timeout},
-	})
+	for _, class := range methodClasses {
+		d.SetTimeout(class, timeout)
+	}
 }
 
 //============================================
@@ -271,7 +411,7 @@ type ClaimCreateOptions struct {
 }
 
 type ChannelCreateOptions struct {
-	ClaimCreateOptions `json:",flatten"`
+	ClaimCreateOptions `json:",omitempty,flatten"`
 	Email              *string  `json:"email,omitempty"`
 	WebsiteURL         *string  `json:"website_url,omitempty"`
 	CoverURL           *string  `json:"cover_url,omitempty"`
@@ -286,7 +426,7 @@ func (d *Client) ChannelCreate(name string, bid float64, options ChannelCreateOp
 		Bid                  string `json:"bid"`
 		FilePath             string `json:"file_path,omitempty"`
 		IncludeProtoBuf      bool   `json:"include_protobuf"`
-		ChannelCreateOptions `json:",flatten"`
+		ChannelCreateOptions `json:",omitempty,flatten"`
 		Blocking             bool `json:"blocking"`
 	}{
 		Name:                 name,
@@ -300,7 +440,7 @@ func (d *Client) ChannelCreate(name string, bid float64, options ChannelCreateOp
 }
 
 type ChannelUpdateOptions struct {
-	ChannelCreateOptions `json:",flatten"`
+	ChannelCreateOptions `json:",omitempty,flatten"`
 	NewSigningKey        *bool   `json:"new_signing_key,omitempty"`
 	ClearFeatured        *bool   `json:"clear_featured,omitempty"`
 	ClearTags            *bool   `json:"clear_tags,omitempty"`
@@ -312,14 +452,14 @@ type ChannelUpdateOptions struct {
 func (d *Client) ChannelUpdate(claimID string, options ChannelUpdateOptions) (*TransactionSummary, error) {
 	response := new(TransactionSummary)
 	args := struct {
-		ClaimID               string `json:"claim_id"`
-		IncludeProtoBuf       bool   `json:"include_protobuf"`
-		*ChannelUpdateOptions `json:",flatten"`
-		Blocking              bool `json:"blocking"`
+		ClaimID              string `json:"claim_id"`
+		IncludeProtoBuf      bool   `json:"include_protobuf"`
+		ChannelUpdateOptions `json:",omitempty,flatten"`
+		Blocking             bool `json:"blocking"`
 	}{
 		ClaimID:              claimID,
 		IncludeProtoBuf:      true,
-		ChannelUpdateOptions: &options,
+		ChannelUpdateOptions: options,
 		Blocking:             true,
 	}
 	structs.DefaultTagName = "json"
@@ -327,7 +467,7 @@ func (d *Client) ChannelUpdate(claimID string, options ChannelUpdateOptions) (*T
 }
 
 type StreamCreateOptions struct {
-	ClaimCreateOptions `json:",flatten"`
+	ClaimCreateOptions `json:",omitempty,flatten"`
 	Fee                *Fee        `json:",omitempty,flatten"`
 	Author             *string     `json:"author,omitempty"`
 	License            *string     `json:"license,omitempty"`
@@ -343,25 +483,27 @@ type StreamCreateOptions struct {
 	ChannelID          *string     `json:"channel_id,omitempty"`
 	ChannelAccountID   *string     `json:"channel_account_id,omitempty"`
 	AccountID          *string     `json:"account_id,omitempty"`
+	ValidateFile       *bool       `json:"validate_file,omitempty"`
+	OptimizeFile       *bool       `json:"optimize_file,omitempty"`
 }
 
 func (d *Client) StreamCreate(name, filePath string, bid float64, options StreamCreateOptions) (*TransactionSummary, error) {
 	response := new(TransactionSummary)
 	args := struct {
-		Name                 string  `json:"name"`
-		Bid                  string  `json:"bid"`
-		FilePath             string  `json:"file_path,omitempty"`
-		FileSize             *string `json:"file_size,omitempty"`
-		IncludeProtoBuf      bool    `json:"include_protobuf"`
-		Blocking             bool    `json:"blocking"`
-		*StreamCreateOptions `json:",flatten"`
+		Name                string  `json:"name"`
+		Bid                 string  `json:"bid"`
+		FilePath            string  `json:"file_path,omitempty"`
+		FileSize            *string `json:"file_size,omitempty"`
+		IncludeProtoBuf     bool    `json:"include_protobuf"`
+		Blocking            bool    `json:"blocking"`
+		StreamCreateOptions `json:",omitempty,flatten"`
 	}{
 		Name:                name,
 		FilePath:            filePath,
 		Bid:                 fmt.Sprintf("%.6f", bid),
 		IncludeProtoBuf:     true,
 		Blocking:            true,
-		StreamCreateOptions: &options,
+		StreamCreateOptions: options,
 	}
 	structs.DefaultTagName = "json"
 	return response, d.Call(response, "stream_create", structs.Map(args))
@@ -391,20 +533,21 @@ type StreamUpdateOptions struct {
 	FilePath             *string `json:"file_path,omitempty"`
 	FileSize             *uint64 `json:"file_size,omitempty"`
 	Bid                  *string `json:"bid,omitempty"`
-	*StreamCreateOptions `json:",flatten"`
+	Replace              *bool   `json:"replace,omitempty"`
+	*StreamCreateOptions `json:",omitempty,flatten"`
 }
 
 func (d *Client) StreamUpdate(claimID string, options StreamUpdateOptions) (*TransactionSummary, error) {
 	response := new(TransactionSummary)
 	args := struct {
-		ClaimID              string `json:"claim_id"`
-		IncludeProtoBuf      bool   `json:"include_protobuf"`
-		*StreamUpdateOptions `json:",flatten"`
-		Blocking             bool `json:"blocking"`
+		ClaimID             string `json:"claim_id"`
+		IncludeProtoBuf     bool   `json:"include_protobuf"`
+		StreamUpdateOptions `json:",omitempty,flatten"`
+		Blocking            bool `json:"blocking"`
 	}{
 		ClaimID:             claimID,
 		IncludeProtoBuf:     true,
-		StreamUpdateOptions: &options,
+		StreamUpdateOptions: options,
 		Blocking:            true,
 	}
 	structs.DefaultTagName = "json"
@@ -523,6 +666,59 @@ func (d *Client) FileList(page uint64, pageSize uint64) (*FileListResponse, erro
 	})
 }
 
+// FileListForSdHash returns the files matching the given sd hash
+func (d *Client) FileListForSdHash(sdHash string) (*FileListResponse, error) {
+	response := new(FileListResponse)
+	return response, d.Call(response, "file_list", map[string]interface{}{
+		"include_protobuf": true,
+		"sd_hash":          sdHash,
+		"page":             1,
+		"page_size":        1,
+	})
+}
+
+var (
+	awaitReflectedMinInterval = 1 * time.Second
+	awaitReflectedMaxInterval = 30 * time.Second
+)
+
+// AwaitFileReflected polls the daemon until the file with the given sd hash is fully uploaded to the reflector, backing
+// off between polls. It gives up when ctx is done, or when the daemon answers a poll with an error.
+func (d *Client) AwaitFileReflected(ctx context.Context, sdHash string) (*File, error) {
+	c := d.WithContext(ctx)
+	policy := retry.Policy{MinInterval: awaitReflectedMinInterval, MaxInterval: awaitReflectedMaxInterval}
+	var file *File
+	err := retry.Do(ctx, policy, func() error {
+		response, err := c.FileListForSdHash(sdHash)
+		if IsDaemonError(err) || errors.Is(err, ErrComponentDisabled) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			// the daemon may be restarting or too busy to answer in time, so keep polling
+			return err
+		}
+		if len(response.Items) == 0 {
+			return errors.Err("file %s not found", sdHash)
+		}
+		if !response.Items[0].IsFullyReflected {
+			return errors.Err("file %s was not reflected (%d blobs remaining)", sdHash, response.Items[0].BlobsRemaining)
+		}
+		file = &response.Items[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// FfmpegFind has the daemon look for ffmpeg again, e.g. after it was installed, and returns what it found. Status
+// reports the result of the last search. Publishing with OptimizeFile needs ffmpeg to be available.
+func (d *Client) FfmpegFind() (*FfmpegStatus, error) {
+	response := new(FfmpegStatus)
+	return response, d.Call(response, "ffmpeg_find", map[string]interface{}{})
+}
+
 func (d *Client) Version() (*VersionResponse, error) {
 	response := new(VersionResponse)
 	return response, d.Call(response, "version", map[string]interface{}{})
@@ -537,28 +733,101 @@ func (d *Client) Resolve(urls string) (*ResolveResponse, error) {
 }
 
 func (d *Client) ClaimSearch(claimName, claimID, txid *string, nout *uint, page uint64, pageSize uint64) (*ClaimSearchResponse, error) {
+	return d.ClaimSearchWithOptions(ClaimSearchOptions{
+		Name:     claimName,
+		ClaimID:  claimID,
+		TXID:     txid,
+		Nout:     nout,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// ClaimSearchOptions are the claim_search filters. Range filters such as Height and ReleaseTime take the daemon's
+// comparison syntax, e.g. ">=1000".
+type ClaimSearchOptions struct {
+	Name                   *string  `json:"name,omitempty"`
+	Text                   *string  `json:"text,omitempty"`
+	ClaimID                *string  `json:"claim_id,omitempty"`
+	ClaimIDs               []string `json:"claim_ids,omitempty"`
+	TXID                   *string  `json:"txid,omitempty"`
+	Nout                   *uint    `json:"nout,omitempty"`
+	Channel                *string  `json:"channel,omitempty"`
+	ChannelIDs             []string `json:"channel_ids,omitempty"`
+	NotChannelIDs          []string `json:"not_channel_ids,omitempty"`
+	ClaimType              []string `json:"claim_type,omitempty"`
+	StreamTypes            []string `json:"stream_types,omitempty"`
+	MediaTypes             []string `json:"media_types,omitempty"`
+	AnyTags                []string `json:"any_tags,omitempty"`
+	AllTags                []string `json:"all_tags,omitempty"`
+	NotTags                []string `json:"not_tags,omitempty"`
+	AnyLanguages           []string `json:"any_languages,omitempty"`
+	AllLanguages           []string `json:"all_languages,omitempty"`
+	NotLanguages           []string `json:"not_languages,omitempty"`
+	AnyLocations           []string `json:"any_locations,omitempty"`
+	Height                 *string  `json:"height,omitempty"`
+	ReleaseTime            *string  `json:"release_time,omitempty"`
+	FeeAmount              *string  `json:"fee_amount,omitempty"`
+	Duration               *string  `json:"duration,omitempty"`
+	IsControlling          *bool    `json:"is_controlling,omitempty"`
+	HasSource              *bool    `json:"has_source,omitempty"`
+	ValidChannelSignature  *bool    `json:"valid_channel_signature,omitempty"`
+	IncludePurchaseReceipt *bool    `json:"include_purchase_receipt,omitempty"`
+	IncludeIsMyOutput      *bool    `json:"include_is_my_output,omitempty"`
+	OrderBy                []string `json:"order_by,omitempty"`
+	NoTotals               *bool    `json:"no_totals,omitempty"`
+	Page                   uint64   `json:"page"`
+	PageSize               uint64   `json:"page_size"`
+}
+
+func (d *Client) ClaimSearchWithOptions(options ClaimSearchOptions) (*ClaimSearchResponse, error) {
 	response := new(ClaimSearchResponse)
 	args := struct {
-		ClaimID         *string `json:"claim_id,omitempty"`
-		TXID            *string `json:"txid,omitempty"`
-		Nout            *uint   `json:"nout,omitempty"`
-		Name            *string `json:"name,omitempty"`
-		IncludeProtobuf bool    `json:"include_protobuf"`
-		Page            uint64  `json:"page"`
-		PageSize        uint64  `json:"page_size"`
+		IncludeProtobuf    bool `json:"include_protobuf"`
+		ClaimSearchOptions `json:",flatten"`
 	}{
-		ClaimID:         claimID,
-		TXID:            txid,
-		Nout:            nout,
-		Name:            claimName,
-		IncludeProtobuf: true,
-		Page:            page,
-		PageSize:        pageSize,
+		IncludeProtobuf:    true,
+		ClaimSearchOptions: options,
 	}
 	structs.DefaultTagName = "json"
 	return response, d.Call(response, "claim_search", structs.Map(args))
 }
 
+// ClaimsForName returns every claim competing for a name, implementing stake.NameResolver
+func (d *Client) ClaimsForName(ctx context.Context, name string) ([]stake.NameClaim, error) {
+	var claims []stake.NameClaim
+	err := d.paginate(ctx, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.ClaimSearch(&name, nil, nil, nil, page, ListAllPageSize)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, claim := range response.Claims {
+			amount, err := ParseDewies(claim.Amount)
+			if err != nil {
+				return 0, 0, err
+			}
+			effectiveAmount := amount
+			if claim.Meta.EffectiveAmount != "" {
+				effectiveAmount, err = ParseDewies(claim.Meta.EffectiveAmount)
+				if err != nil {
+					return 0, 0, err
+				}
+			}
+			claims = append(claims, stake.NameClaim{
+				ClaimID:         claim.ClaimID,
+				Amount:          uint64(amount),
+				EffectiveAmount: uint64(effectiveAmount),
+				IsControlling:   claim.Meta.IsControlling,
+			})
+		}
+		return response.TotalPages, len(response.Claims), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
 func (d *Client) ChannelExport(channelClaimID string, channelName, accountID *string) (*ChannelExportResponse, error) {
 	response := new(ChannelExportResponse)
 	return response, d.Call(response, "channel_export", map[string]interface{}{
@@ -616,7 +885,7 @@ func (d *Client) SupportAbandon(claimID *string, txid *string, nout *uint, keep
 	response := new(TransactionSummary)
 	args := struct {
 		ClaimID   *string `json:"claim_id,omitempty"`
-		TxID      *string `json:"claim_id,omitempty"`
+		TxID      *string `json:"txid,omitempty"`
 		Nout      *uint   `json:"nout,omitempty"`
 		AccountID *string `json:"account_id,omitempty"`
 		Preview   bool    `json:"preview,omitempty"`
@@ -642,7 +911,7 @@ func (d *Client) TxoSpend(txoType, claimID, txid, channelID, name, accountID *st
 		ClaimID       *string `json:"claim_id,omitempty"`
 		ChannelID     *string `json:"channel_id,omitempty"`
 		Name          *string `json:"name,omitempty"`
-		TxID          *string `json:"claim_id,omitempty"`
+		TxID          *string `json:"txid,omitempty"`
 		Type          *string `json:"type,omitempty"`
 		AccountID     *string `json:"account_id,omitempty"`
 		Preview       bool    `json:"preview,omitempty"`
@@ -663,6 +932,209 @@ func (d *Client) TxoSpend(txoType, claimID, txid, channelID, name, accountID *st
 	return response, d.Call(response, "txo_spend", structs.Map(args))
 }
 
+// TxoListOptions are the txo_list filters
+type TxoListOptions struct {
+	Type                     []string `json:"type,omitempty"`
+	TXID                     []string `json:"txid,omitempty"`
+	ClaimID                  []string `json:"claim_id,omitempty"`
+	ChannelID                []string `json:"channel_id,omitempty"`
+	Name                     []string `json:"name,omitempty"`
+	IsSpent                  *bool    `json:"is_spent,omitempty"`
+	IsNotSpent               *bool    `json:"is_not_spent,omitempty"`
+	IsMyInputOrOutput        *bool    `json:"is_my_input_or_output,omitempty"`
+	IsMyOutput               *bool    `json:"is_my_output,omitempty"`
+	IsNotMyOutput            *bool    `json:"is_not_my_output,omitempty"`
+	IsMyInput                *bool    `json:"is_my_input,omitempty"`
+	IsNotMyInput             *bool    `json:"is_not_my_input,omitempty"`
+	ExcludeInternalTransfers *bool    `json:"exclude_internal_transfers,omitempty"`
+	IncludeReceivedTips      *bool    `json:"include_received_tips,omitempty"`
+	AccountID                *string  `json:"account_id,omitempty"`
+	WalletID                 *string  `json:"wallet_id,omitempty"`
+	Resolve                  *bool    `json:"resolve,omitempty"`
+	OrderBy                  *string  `json:"order_by,omitempty"`
+	NoTotals                 *bool    `json:"no_totals,omitempty"`
+	Page                     uint64   `json:"page"`
+	PageSize                 uint64   `json:"page_size"`
+}
+
+func (d *Client) TxoList(options TxoListOptions) (*TxoListResponse, error) {
+	response := new(TxoListResponse)
+	args := struct {
+		IncludeProtobuf bool `json:"include_protobuf"`
+		TxoListOptions  `json:",flatten"`
+	}{
+		IncludeProtobuf: true,
+		TxoListOptions:  options,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "txo_list", structs.Map(args))
+}
+
+type PurchaseCreateOptions struct {
+	URL                    *string  `json:"url,omitempty"`
+	WalletID               *string  `json:"wallet_id,omitempty"`
+	FundingAccountIDs      []string `json:"funding_account_ids,omitempty"`
+	AllowDuplicatePurchase *bool    `json:"allow_duplicate_purchase,omitempty"`
+	OverrideMaxKeyFee      *bool    `json:"override_max_key_fee,omitempty"`
+	Preview                *bool    `json:"preview,omitempty"`
+}
+
+// PurchaseCreate pays the fee of a paid stream. Either claimID or options.URL must be set.
+func (d *Client) PurchaseCreate(claimID *string, options PurchaseCreateOptions) (*TransactionSummary, error) {
+	if claimID == nil && options.URL == nil {
+		return nil, errors.Err("either claimID or url must be supplied")
+	}
+	response := new(TransactionSummary)
+	args := struct {
+		ClaimID               *string `json:"claim_id,omitempty"`
+		Blocking              bool    `json:"blocking"`
+		PurchaseCreateOptions `json:",omitempty,flatten"`
+	}{
+		ClaimID:               claimID,
+		Blocking:              true,
+		PurchaseCreateOptions: options,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "purchase_create", structs.Map(args))
+}
+
+func (d *Client) PurchaseList(claimID *string, accountID *string, page uint64, pageSize uint64) (*PurchaseListResponse, error) {
+	response := new(PurchaseListResponse)
+	args := struct {
+		ClaimID   *string `json:"claim_id,omitempty"`
+		AccountID *string `json:"account_id,omitempty"`
+		Resolve   bool    `json:"resolve"`
+		Page      uint64  `json:"page"`
+		PageSize  uint64  `json:"page_size"`
+	}{
+		ClaimID:   claimID,
+		AccountID: accountID,
+		Resolve:   true,
+		Page:      page,
+		PageSize:  pageSize,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "purchase_list", structs.Map(args))
+}
+
+type CollectionCreateOptions struct {
+	ClaimCreateOptions `json:",omitempty,flatten"`
+	ChannelID          *string `json:"channel_id,omitempty"`
+	ChannelName        *string `json:"channel_name,omitempty"`
+	ChannelAccountID   *string `json:"channel_account_id,omitempty"`
+	WalletID           *string `json:"wallet_id,omitempty"`
+}
+
+// CollectionCreate creates a collection (playlist) claim listing the given claim IDs
+func (d *Client) CollectionCreate(name string, bid float64, claims []string, options CollectionCreateOptions) (*TransactionSummary, error) {
+	response := new(TransactionSummary)
+	args := struct {
+		Name                    string   `json:"name"`
+		Bid                     string   `json:"bid"`
+		Claims                  []string `json:"claims"`
+		IncludeProtoBuf         bool     `json:"include_protobuf"`
+		Blocking                bool     `json:"blocking"`
+		CollectionCreateOptions `json:",omitempty,flatten"`
+	}{
+		Name:                    name,
+		Bid:                     fmt.Sprintf("%.6f", bid),
+		Claims:                  claims,
+		IncludeProtoBuf:         true,
+		Blocking:                true,
+		CollectionCreateOptions: options,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "collection_create", structs.Map(args))
+}
+
+type CollectionUpdateOptions struct {
+	CollectionCreateOptions `json:",omitempty,flatten"`
+	Claims                  []string `json:"claims,omitempty"`
+	ClearClaims             *bool    `json:"clear_claims,omitempty"`
+	ClearTags               *bool    `json:"clear_tags,omitempty"`
+	ClearLanguages          *bool    `json:"clear_languages,omitempty"`
+	ClearLocations          *bool    `json:"clear_locations,omitempty"`
+	Replace                 *bool    `json:"replace,omitempty"`
+	Bid                     *string  `json:"bid,omitempty"`
+}
+
+func (d *Client) CollectionUpdate(claimID string, options CollectionUpdateOptions) (*TransactionSummary, error) {
+	response := new(TransactionSummary)
+	args := struct {
+		ClaimID                 string `json:"claim_id"`
+		IncludeProtoBuf         bool   `json:"include_protobuf"`
+		Blocking                bool   `json:"blocking"`
+		CollectionUpdateOptions `json:",omitempty,flatten"`
+	}{
+		ClaimID:                 claimID,
+		IncludeProtoBuf:         true,
+		Blocking:                true,
+		CollectionUpdateOptions: options,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "collection_update", structs.Map(args))
+}
+
+func (d *Client) CollectionAbandon(claimID *string, txid *string, nout *uint, accountID *string) (*TransactionSummary, error) {
+	if claimID == nil && (txid == nil || nout == nil) {
+		return nil, errors.Err("either claimID or txid+nout must be supplied")
+	}
+	response := new(TransactionSummary)
+	args := struct {
+		ClaimID   *string `json:"claim_id,omitempty"`
+		TxID      *string `json:"txid,omitempty"`
+		Nout      *uint   `json:"nout,omitempty"`
+		AccountID *string `json:"account_id,omitempty"`
+		Blocking  bool    `json:"blocking"`
+	}{
+		ClaimID:   claimID,
+		TxID:      txid,
+		Nout:      nout,
+		AccountID: accountID,
+		Blocking:  true,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "collection_abandon", structs.Map(args))
+}
+
+func (d *Client) CollectionList(accountID *string, resolveClaims uint64, page uint64, pageSize uint64) (*CollectionListResponse, error) {
+	response := new(CollectionListResponse)
+	args := struct {
+		AccountID     *string `json:"account_id,omitempty"`
+		ResolveClaims uint64  `json:"resolve_claims,omitempty"`
+		Page          uint64  `json:"page"`
+		PageSize      uint64  `json:"page_size"`
+	}{
+		AccountID:     accountID,
+		ResolveClaims: resolveClaims,
+		Page:          page,
+		PageSize:      pageSize,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "collection_list", structs.Map(args))
+}
+
+// CollectionResolve resolves the claims listed in a collection. Either claimID or url must be set.
+func (d *Client) CollectionResolve(claimID *string, url *string, page uint64, pageSize uint64) (*CollectionResolveResponse, error) {
+	if claimID == nil && url == nil {
+		return nil, errors.Err("either claimID or url must be supplied")
+	}
+	response := new(CollectionResolveResponse)
+	args := struct {
+		ClaimID  *string `json:"claim_id,omitempty"`
+		URL      *string `json:"url,omitempty"`
+		Page     uint64  `json:"page"`
+		PageSize uint64  `json:"page_size"`
+	}{
+		ClaimID:  claimID,
+		URL:      url,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	structs.DefaultTagName = "json"
+	return response, d.Call(response, "collection_resolve", structs.Map(args))
+}
+
 func (d *Client) AccountAdd(accountName string, seed *string, privateKey *string, publicKey *string, singleKey *bool, walletID *string) (*Account, error) {
 	response := new(Account)
 
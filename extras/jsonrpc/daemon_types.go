@@ -115,6 +115,13 @@ func fixDecodeProto(src, dest reflect.Type, data interface{}) (interface{}, erro
 			return d, nil
 		}
 
+	case reflect.TypeOf(Dewies(0)):
+		if n, ok := data.(json.Number); ok {
+			return ParseDewies(n.String())
+		} else if s, ok := data.(string); ok {
+			return ParseDewies(s)
+		}
+
 	case reflect.TypeOf(lbryschema.Fee_Currency(0)):
 		val, err := getEnumVal(lbryschema.Fee_Currency_value, data)
 		return lbryschema.Fee_Currency(val), err
@@ -134,7 +141,8 @@ func fixDecodeProto(src, dest reflect.Type, data interface{}) (interface{}, erro
 	return data, nil
 }
 
-type WalletBalanceResponse decimal.Decimal
+// WalletBalanceResponse is an alias, so it decodes like Dewies
+type WalletBalanceResponse = Dewies
 
 type PeerListResponsePeer struct {
 	IP     string `json:"host"`
@@ -201,7 +209,7 @@ type Account struct {
 		} `json:"receiving"`
 	} `json:"address_generator"`
 	Certificates uint64   `json:"certificates"`
-	Coins        float64  `json:"coins"`
+	Coins        Dewies   `json:"coins"`
 	Encrypted    bool     `json:"encrypted"`
 	ID           string   `json:"id"`
 	IsDefault    bool     `json:"is_default"`
@@ -211,9 +219,10 @@ type Account struct {
 	Preferences  *struct {
 		Theme string `json:"theme"`
 	} `json:"preferences,omitempty"`
-	PrivateKey *string `json:"private_key,omitempty"`
+	// PrivateKey and Seed are only returned by AccountExportSeed
+	PrivateKey *Secret `json:"private_key,omitempty"`
 	PublicKey  string  `json:"public_key"`
-	Seed       *string `json:"seed,omitempty"`
+	Seed       *Secret `json:"seed,omitempty"`
 	Satoshis   uint64  `json:"satoshis"`
 }
 
@@ -225,14 +234,14 @@ type AccountListResponse struct {
 }
 
 type AccountBalanceResponse struct {
-	Available         decimal.Decimal `json:"available"`
-	Reserved          decimal.Decimal `json:"reserved"`
+	Available         Dewies `json:"available"`
+	Reserved          Dewies `json:"reserved"`
 	ReservedSubtotals struct {
-		Claims   decimal.Decimal `json:"claims"`
-		Supports decimal.Decimal `json:"supports"`
-		Tips     decimal.Decimal `json:"tips"`
+		Claims   Dewies `json:"claims"`
+		Supports Dewies `json:"supports"`
+		Tips     Dewies `json:"tips"`
 	} `json:"reserved_subtotals"`
-	Total decimal.Decimal `json:"total"`
+	Total Dewies `json:"total"`
 }
 
 type Transaction struct {
@@ -306,7 +315,7 @@ type Support struct {
 }
 
 type PurchaseReceipt struct {
-	Address       string `json:"file_name"`
+	Address       string `json:"address"`
 	Amount        string `json:"amount"`
 	ClaimID       string `json:"claim_id"`
 	Confirmations int    `json:"confirmations"`
@@ -314,7 +323,7 @@ type PurchaseReceipt struct {
 	Nout          uint64 `json:"nout"`
 	Timestamp     uint64 `json:"timestamp"`
 	Txid          string `json:"txid"`
-	Type          string `json:"purchase"`
+	Type          string `json:"type"`
 }
 
 type Claim struct {
@@ -449,6 +458,24 @@ type SupportListResponse struct {
 	TotalPages uint64 `json:"total_pages"`
 }
 
+type TxoListResponse struct {
+	Items      []Transaction `json:"items"`
+	Page       uint64        `json:"page"`
+	PageSize   uint64        `json:"page_size"`
+	TotalPages uint64        `json:"total_pages"`
+	TotalItems uint64        `json:"total_items"`
+}
+
+type PurchaseListResponse struct {
+	Items      []PurchaseReceipt `json:"items"`
+	Page       uint64            `json:"page"`
+	PageSize   uint64            `json:"page_size"`
+	TotalPages uint64            `json:"total_pages"`
+}
+
+type CollectionListResponse ClaimListResponse
+type CollectionResolveResponse ClaimListResponse
+
 type StatusResponse struct {
 	BlobManager struct {
 		Connections struct {
@@ -469,12 +496,8 @@ type StatusResponse struct {
 		NodeID              string `json:"node_id"`
 		PeersInRoutingTable uint64 `json:"peers_in_routing_table"`
 	} `json:"dht"`
-	FfmpegStatus struct {
-		AnalyzeAudioVolume bool   `json:"analyze_audio_volume"`
-		Available          bool   `json:"available"`
-		Which              string `json:"which"`
-	} `json:"ffmpeg_status"`
-	FileManager struct {
+	FfmpegStatus FfmpegStatus `json:"ffmpeg_status"`
+	FileManager  struct {
 		ManagedFiles int64 `json:"managed_files"`
 	} `json:"file_manager"`
 	HashAnnouncer struct {
@@ -572,7 +595,7 @@ type transactionListBlob struct {
 	Nout         int    `json:"nout"`
 }
 
-//TODO: this repeats all the fields from transactionListBlob which doesn't make sense
+// TODO: this repeats all the fields from transactionListBlob which doesn't make sense
 // but if i extend the type with transactionListBlob it doesn't fill the fields. does our unmarshaller crap out on these?
 type supportBlob struct {
 	Address      string `json:"address"`
@@ -603,6 +626,13 @@ type TransactionListResponse struct {
 	TotalPages uint64 `json:"total_pages"`
 }
 
+// FfmpegStatus tells whether the daemon found ffmpeg, which it needs to optimize files on publish
+type FfmpegStatus struct {
+	AnalyzeAudioVolume bool   `json:"analyze_audio_volume"`
+	Available          bool   `json:"available"`
+	Which              string `json:"which"`
+}
+
 type VersionResponse struct {
 	Build   string `json:"build"`
 	Desktop string `json:"desktop"`
@@ -0,0 +1,81 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"github.com/ybbus/jsonrpc"
+)
+
+// flightGroup coalesces identical read calls that are in flight at the same time, so the daemon answers them once.
+// It is shared by a client and its copies.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done     chan struct{}
+	response *jsonrpc.RPCResponse
+	err      error
+	// canceled is true if the call failed because the context of the caller that made it was done
+	canceled bool
+}
+
+// SetDeduplicateReads turns on coalescing of identical concurrent read calls. When it is on, a read call with the same
+// method and params as one already in flight waits for that call and gets its response, instead of calling the daemon
+// again. This helps when many workers resolve the same claims at once. Only copies of the client made after it is
+// turned on share the calls in flight. Callers of CallNoDecode may get the same result value, so they must not modify
+// it.
+func (d *Client) SetDeduplicateReads(enabled bool) {
+	if !enabled {
+		d.flights = nil
+		return
+	}
+	if d.flights == nil {
+		d.flights = &flightGroup{flights: make(map[string]*flight)}
+	}
+}
+
+// doDeduplicated sends the request like do, but shares the response of an identical read call already in flight
+func (d *Client) doDeduplicated(ctx context.Context, request *jsonrpc.RPCRequest) (*jsonrpc.RPCResponse, error) {
+	if d.flights == nil || ClassOf(request.Method) != MethodClassRead {
+		return d.do(ctx, request)
+	}
+	params, err := json.Marshal(request.Params)
+	if err != nil {
+		return d.do(ctx, request)
+	}
+	key := request.Method + " " + string(params)
+
+	g := d.flights
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, errors.Err("rpc call %s(): %w", request.Method, ctx.Err())
+		}
+		if f.canceled {
+			// the call was given up by the caller that made it, not by this one
+			return d.do(ctx, request)
+		}
+		return f.response, f.err
+	}
+	f := &flight{done: make(chan struct{})}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	f.response, f.err = d.do(ctx, request)
+	f.canceled = f.err != nil && ctx.Err() != nil
+
+	g.mu.Lock()
+	delete(g.flights, key)
+	g.mu.Unlock()
+	close(f.done)
+	return f.response, f.err
+}
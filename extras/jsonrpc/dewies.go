@@ -0,0 +1,76 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// DewiesPerLBC is the number of dewies (the smallest unit of LBC) in one LBC
+const DewiesPerLBC = 100000000
+
+const dewiesPrecision = 8
+
+// Dewies is a fixed-point amount of LBC. The daemon reports balances and amounts as decimal strings; decoding them into
+// Dewies instead of a float keeps comparisons and arithmetic exact.
+type Dewies int64
+
+// ParseDewies parses an LBC amount such as "1.25" into Dewies. Amounts with more than 8 decimal places are rejected
+// rather than silently rounded.
+func ParseDewies(lbc string) (Dewies, error) {
+	d, err := decimal.NewFromString(lbc)
+	if err != nil {
+		return 0, errors.Err(err)
+	}
+	return DewiesFromDecimal(d)
+}
+
+// DewiesFromDecimal converts an LBC amount to Dewies. It fails if the amount is more precise than one dewey.
+func DewiesFromDecimal(lbc decimal.Decimal) (Dewies, error) {
+	shifted := lbc.Shift(dewiesPrecision)
+	if !shifted.Equal(shifted.Truncate(0)) {
+		return 0, errors.Err("amount %s is more precise than one dewey", lbc.String())
+	}
+	return Dewies(shifted.IntPart()), nil
+}
+
+// DewiesFromFloat converts a float LBC amount to Dewies, rounding to the nearest dewey.
+func DewiesFromFloat(lbc float64) Dewies {
+	return Dewies(decimal.NewFromFloat(lbc).Shift(dewiesPrecision).Round(0).IntPart())
+}
+
+// Decimal returns the amount in LBC. It exists so callers that used the previous decimal.Decimal fields can keep working.
+func (d Dewies) Decimal() decimal.Decimal {
+	return decimal.New(int64(d), -dewiesPrecision)
+}
+
+// Float64 returns the amount in LBC as a float. Only use it for display, never for comparisons.
+func (d Dewies) Float64() float64 {
+	f, _ := d.Decimal().Float64()
+	return f
+}
+
+// String returns the amount in LBC with all 8 decimal places, the same format the daemon uses
+func (d Dewies) String() string {
+	return d.Decimal().StringFixed(dewiesPrecision)
+}
+
+func (d Dewies) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Dewies) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseDewies(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
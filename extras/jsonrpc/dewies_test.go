@@ -0,0 +1,92 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDewies(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Dewies
+	}{
+		{"0", 0},
+		{"1", DewiesPerLBC},
+		{"0.1", 10000000},
+		{"12.34567891", 1234567891},
+		{"-0.00000001", -1},
+	}
+	for _, test := range tests {
+		got, err := ParseDewies(test.in)
+		if err != nil {
+			t.Errorf("%s: %v", test.in, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.in, test.expected, got)
+		}
+	}
+
+	_, err := ParseDewies("0.000000001")
+	if err == nil {
+		t.Error("expected an error for an amount smaller than one dewey")
+	}
+}
+
+func TestDewies_NoFloatRounding(t *testing.T) {
+	a, _ := ParseDewies("0.1")
+	b, _ := ParseDewies("0.2")
+	c, _ := ParseDewies("0.3")
+	if a+b != c {
+		t.Errorf("expected 0.1 + 0.2 == 0.3, got %s", (a + b).String())
+	}
+}
+
+func TestDewies_Decode(t *testing.T) {
+	response := new(AccountBalanceResponse)
+	err := Decode(map[string]interface{}{
+		"available": "10.5",
+		"reserved":  json.Number("0.00000001"),
+		"total":     "10.50000001",
+	}, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Available+response.Reserved != response.Total {
+		t.Errorf("expected %s + %s == %s", response.Available, response.Reserved, response.Total)
+	}
+	if response.Total.String() != "10.50000001" {
+		t.Errorf("unexpected total %s", response.Total.String())
+	}
+}
+
+func TestDewies_JSON(t *testing.T) {
+	b, err := json.Marshal(Dewies(150000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"1.50000000"` {
+		t.Errorf("unexpected json %s", b)
+	}
+	var d Dewies
+	err = json.Unmarshal(b, &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 150000000 {
+		t.Errorf("expected 150000000, got %d", d)
+	}
+}
+
+func TestDewies_DecodeWalletBalance(t *testing.T) {
+	for _, data := range []interface{}{"1.5", json.Number("1.5")} {
+		var balance WalletBalanceResponse
+		err := Decode(data, &balance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance != 150000000 {
+			t.Errorf("expected 150000000 dewies from %v, got %d", data, balance)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package jsonrpc
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/ybbus/jsonrpc"
+)
+
+// FailoverOptions configures a client that spreads over several daemons
+type FailoverOptions struct {
+	// RecheckInterval is how long a daemon that failed is skipped before its status is checked again. Defaults to 30
+	// seconds.
+	RecheckInterval time.Duration
+}
+
+// EndpointStatus is what a failover client knows about one of its daemons
+type EndpointStatus struct {
+	Address   string
+	Healthy   bool
+	Active    bool // calls currently go to this daemon
+	LastError error
+}
+
+type endpoint struct {
+	address    string
+	healthy    bool
+	checkAfter time.Time
+	lastErr    error
+}
+
+// endpointPool is shared by a failover client and its copies
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	active    int
+	recheck   time.Duration
+}
+
+// NewFailoverClient returns a client that sends every call to one daemon and moves to the next one when that daemon
+// can't be reached. It sticks with the daemon it moved to until that one fails too. A daemon that failed is skipped
+// until its status call succeeds again. The daemons are expected to run with the same configuration and wallet.
+func NewFailoverClient(addresses []string, options FailoverOptions) *Client {
+	if len(addresses) == 0 {
+		return NewClient("")
+	}
+	d := NewClient(addresses[0])
+	if options.RecheckInterval == 0 {
+		options.RecheckInterval = 30 * time.Second
+	}
+	pool := &endpointPool{recheck: options.RecheckInterval}
+	for _, address := range addresses {
+		pool.endpoints = append(pool.endpoints, &endpoint{address: address, healthy: true})
+	}
+	d.endpoints = pool
+	return d
+}
+
+// Endpoints returns the state of each daemon of a failover client, in the order they were given. It returns nil for a
+// client created with NewClient.
+func (d *Client) Endpoints() []EndpointStatus {
+	if d.endpoints == nil {
+		return nil
+	}
+	p := d.endpoints
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		statuses[i] = EndpointStatus{Address: e.address, Healthy: e.healthy, Active: i == p.active, LastError: e.lastErr}
+	}
+	return statuses
+}
+
+// CheckEndpoints calls status on every daemon of a failover client and updates their health. It can be run
+// periodically so a recovered daemon is noticed before calls need it.
+func (d *Client) CheckEndpoints(ctx context.Context) []EndpointStatus {
+	if d.endpoints == nil {
+		return nil
+	}
+	p := d.endpoints
+	p.mu.Lock()
+	endpoints := append([]*endpoint(nil), p.endpoints...)
+	p.mu.Unlock()
+
+	for _, e := range endpoints {
+		d.checkEndpoint(ctx, e)
+	}
+	return d.Endpoints()
+}
+
+// doFailover sends the request to the active daemon, moving on to the others if it can't be reached
+func (d *Client) doFailover(ctx context.Context, request *jsonrpc.RPCRequest) (*jsonrpc.RPCResponse, error) {
+	p := d.endpoints
+	var lastErr error
+	for _, i := range p.candidates() {
+		e := p.endpoints[i]
+		if !p.usable(e) && !d.checkEndpoint(ctx, e) {
+			continue
+		}
+
+		response, err := d.post(ctx, e.address, request)
+		if err == nil {
+			p.activate(i)
+			return response, nil
+		}
+		lastErr = err
+		if d.ctx.Err() != nil {
+			// the caller gave up, which says nothing about the daemon
+			return nil, err
+		}
+		// running out of the call's timeout does count against the daemon
+		p.markFailed(e, err)
+		if ctx.Err() != nil {
+			// but leaves no time to try the next one
+			return nil, err
+		}
+		if ClassOf(request.Method) != MethodClassRead && !unreachable(err) {
+			// the daemon may have acted on the request, so sending it to another one could do it twice
+			return nil, err
+		}
+		log.Warnf("jsonrpc: %s failed on %s, trying the next daemon: %v", request.Method, e.address, err)
+	}
+	if lastErr == nil {
+		lastErr = errors.Err("rpc call %s(): no daemon is healthy", request.Method)
+	}
+	return nil, lastErr
+}
+
+// activeAddress returns the address of the daemon calls go to first
+func (d *Client) activeAddress() string {
+	if d.endpoints == nil {
+		return d.address
+	}
+	p := d.endpoints
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.endpoints[p.active].address
+}
+
+// candidates returns the endpoint indexes in the order they should be tried, starting with the active one. Unhealthy
+// endpoints are left out unless every endpoint is unhealthy.
+func (p *endpointPool) candidates() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var healthy, all []int
+	for n := 0; n < len(p.endpoints); n++ {
+		i := (p.active + n) % len(p.endpoints)
+		all = append(all, i)
+		if p.endpoints[i].healthy || time.Now().After(p.endpoints[i].checkAfter) {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) == 0 {
+		return all
+	}
+	return healthy
+}
+
+// usable returns true if calls can go to the endpoint without checking it first
+func (p *endpointPool) usable(e *endpoint) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return e.healthy
+}
+
+func (p *endpointPool) activate(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active != i {
+		log.Infof("jsonrpc: switched to daemon %s", p.endpoints[i].address)
+	}
+	p.active = i
+	p.endpoints[i].healthy = true
+	p.endpoints[i].lastErr = nil
+}
+
+func (p *endpointPool) markFailed(e *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.healthy = false
+	e.lastErr = err
+	e.checkAfter = time.Now().Add(p.recheck)
+}
+
+// checkEndpoint calls status on the daemon and records whether it is running
+func (d *Client) checkEndpoint(ctx context.Context, e *endpoint) bool {
+	status, err := d.statusAt(ctx, e.address)
+	if err == nil && !status.IsRunning {
+		err = errors.Err("daemon at %s is still starting", e.address)
+	}
+
+	p := d.endpoints
+	if err != nil {
+		p.markFailed(e, err)
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.healthy = true
+	e.lastErr = nil
+	return true
+}
+
+// unreachable returns true if the error shows the request never got to the daemon
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	return stderrors.As(errors.Unwrap(err), &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,326 @@
+// Package jsonrpctest records the calls made to a real daemon and replays them, so code that drives the daemon, such as
+// wallet setup or publishing, can be tested end to end without one.
+//
+// Record once against a daemon with a throwaway wallet. Seeds, private keys and passwords are redacted from the fixture,
+// but it holds everything else the daemon returned:
+//
+//	recorder := jsonrpctest.NewRecorder("http://localhost:5279")
+//	runSync(jsonrpc.NewClient(recorder.URL))
+//	recorder.Close()
+//	err := recorder.Fixture().Save("testdata/sync.json")
+//
+// Then replay it in tests:
+//
+//	fixture, err := jsonrpctest.LoadFixture("testdata/sync.json")
+//	server := jsonrpctest.NewReplayServer(fixture, "file_path")
+//	defer server.Close()
+//	runSync(jsonrpc.NewClient(server.URL))
+package jsonrpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/extras/jsonrpc"
+)
+
+// Interaction is one call to the daemon and its answer
+type Interaction struct {
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+	Result json.RawMessage        `json:"result,omitempty"`
+	Error  *RPCError              `json:"error,omitempty"`
+}
+
+// RPCError is an error returned by the daemon
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Fixture is a recorded sequence of interactions
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadFixture reads a fixture saved with Save
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	f := &Fixture{}
+	err = json.Unmarshal(data, f)
+	if err != nil {
+		return nil, errors.Prefix("fixture "+path, err)
+	}
+	return f, nil
+}
+
+// Save writes the fixture as indented json, so changes to it are easy to review
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Err(err)
+	}
+	return errors.Err(ioutil.WriteFile(path, append(data, '\n'), 0644))
+}
+
+type request struct {
+	ID     interface{}            `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// Recorder is a proxy in front of a daemon that records every call made through it
+type Recorder struct {
+	*httptest.Server
+	target  string
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder starts a recorder that forwards calls to the daemon at target. Point the client at its URL.
+func NewRecorder(target string) *Recorder {
+	r := &Recorder{target: target}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+func (r *Recorder) serve(w http.ResponseWriter, httpRequest *http.Request) {
+	body, err := ioutil.ReadAll(httpRequest.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req request
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	forward, err := http.NewRequestWithContext(httpRequest.Context(), http.MethodPost, r.target, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	forward.Header.Set("Content-Type", "application/json")
+	daemonResponse, err := http.DefaultClient.Do(forward)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer daemonResponse.Body.Close()
+	responseBody, err := ioutil.ReadAll(daemonResponse.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	var resp response
+	if json.Unmarshal(responseBody, &resp) == nil {
+		r.mu.Lock()
+		r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+			Method: req.Method,
+			Params: redactParams(req.Params),
+			Result: redactResult(req.Method, req.Params, resp.Result),
+			Error:  resp.Error,
+		})
+		r.mu.Unlock()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(daemonResponse.StatusCode)
+	_, _ = w.Write(responseBody)
+}
+
+// redacted replaces seeds, private keys and passwords in recorded interactions
+const redacted = "[redacted]"
+
+func redactParams(params map[string]interface{}) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	kept := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if v != nil && jsonrpc.IsSensitiveParam(k) {
+			v = redacted
+		}
+		kept[k] = v
+	}
+	return kept
+}
+
+// redactResult redacts the fields of the result that are named like sensitive params, such as the seeds account_list
+// returns. A result that is not an object or array, such as the one of channel_export, is redacted whole if the call
+// returns seeds or private keys.
+func redactResult(method string, params map[string]interface{}, result json.RawMessage) json.RawMessage {
+	if len(result) == 0 {
+		return result
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	decoder.UseNumber()
+	err := decoder.Decode(&value)
+	if err == nil {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			if !redactValue(value) {
+				return result
+			}
+			encoded, err := json.Marshal(value)
+			if err == nil {
+				return encoded
+			}
+		}
+	}
+	if jsonrpc.IsSensitiveCall(method, params) {
+		encoded, _ := json.Marshal(redacted)
+		return encoded
+	}
+	return result
+}
+
+// redactValue redacts sensitive fields of the decoded json value in place, and returns true if there were any
+func redactValue(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if field != nil && jsonrpc.IsSensitiveParam(k) {
+				v[k] = redacted
+				changed = true
+			} else if redactValue(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactValue(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// Fixture returns the interactions recorded so far
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Fixture{Interactions: append([]Interaction(nil), r.fixture.Interactions...)}
+}
+
+// ReplayServer answers calls with the responses from a fixture. Calls are matched by method and params, and calls
+// that match the same interactions get their responses in recorded order. Once those run out, the last one is
+// repeated, so polling loops end the way they did when recording. Calls that match nothing get a daemon error.
+type ReplayServer struct {
+	*httptest.Server
+	ignore map[string]bool
+	mu     sync.Mutex
+	queues map[string][]*replayed
+	all    []*replayed
+}
+
+type replayed struct {
+	Interaction
+	used bool
+}
+
+// NewReplayServer starts a server that replays the fixture. Params named in ignoreParams are left out when matching
+// calls, for params that change from run to run, such as temporary file paths. Sensitive params are always left out,
+// since they are redacted when recording.
+func NewReplayServer(f *Fixture, ignoreParams ...string) *ReplayServer {
+	s := &ReplayServer{ignore: make(map[string]bool), queues: make(map[string][]*replayed)}
+	for _, param := range ignoreParams {
+		s.ignore[param] = true
+	}
+	for _, interaction := range f.Interactions {
+		r := &replayed{Interaction: interaction}
+		key := s.key(interaction.Method, interaction.Params)
+		s.queues[key] = append(s.queues[key], r)
+		s.all = append(s.all, r)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// key identifies a call by its method and the params that are not ignored. Params are compared by their json
+// encoding, so recorded and replayed numbers match.
+func (s *ReplayServer) key(method string, params map[string]interface{}) string {
+	kept := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if !s.ignore[k] && !jsonrpc.IsSensitiveParam(k) && v != nil {
+			kept[k] = v
+		}
+	}
+	encoded, err := json.Marshal(kept)
+	if err != nil {
+		return method
+	}
+	// decode and encode again, so numbers are formatted the same way whatever type they were given as
+	var normalized interface{}
+	_ = json.Unmarshal(encoded, &normalized)
+	encoded, _ = json.Marshal(normalized)
+	return method + " " + string(encoded)
+}
+
+func (s *ReplayServer) serve(w http.ResponseWriter, httpRequest *http.Request) {
+	var req request
+	err := json.NewDecoder(httpRequest.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	key := s.key(req.Method, req.Params)
+	s.mu.Lock()
+	queue := s.queues[key]
+	var next *replayed
+	for _, r := range queue {
+		if !r.used {
+			next = r
+			break
+		}
+	}
+	if next == nil && len(queue) > 0 {
+		next = queue[len(queue)-1]
+	}
+	if next != nil {
+		next.used = true
+		resp.Result = next.Result
+		resp.Error = next.Error
+	} else {
+		resp.Error = &RPCError{Code: -32601, Message: "jsonrpctest: no recorded response for " + key}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Unused returns the interactions that were never replayed, so tests can check the code made every recorded call
+func (s *ReplayServer) Unused() []Interaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var unused []Interaction
+	for _, r := range s.all {
+		if !r.used {
+			unused = append(unused, r.Interaction)
+		}
+	}
+	return unused
+}
@@ -0,0 +1,203 @@
+package jsonrpctest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbry.go/v2/extras/jsonrpc"
+)
+
+// newDaemon answers like a daemon whose wallet fills up as addresses are generated
+func newDaemon(t *testing.T) *httptest.Server {
+	addresses := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "status":
+			resp["result"] = map[string]interface{}{"skipped_components": []string{}}
+		case "address_unused":
+			addresses++
+			resp["result"] = "bAddress" + string(rune('0'+addresses))
+		case "account_balance":
+			resp["result"] = map[string]interface{}{"available": "1.5", "total": "1.5"}
+		case "account_list":
+			account := map[string]interface{}{"id": "account", "public_key": "xpub"}
+			if req.Params["show_seed"] == true {
+				account["seed"] = "abandon abandon abandon"
+				account["private_key"] = "xprv"
+			}
+			resp["result"] = map[string]interface{}{"items": []interface{}{account}}
+		case "channel_export":
+			resp["result"] = "base58 export"
+		case "wallet_unlock":
+			resp["result"] = req.Params["password"] == "hunter2"
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "unknown method " + req.Method}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	recorder := NewRecorder(newDaemon(t).URL)
+	client := jsonrpc.NewClient(recorder.URL)
+	first, err := client.AddressUnused(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.AddressUnused(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := client.AccountBalance(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Version()
+	if err == nil {
+		t.Fatal("expected the daemon error for version")
+	}
+	recorder.Close()
+
+	dir, err := ioutil.TempDir("", "jsonrpctest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixture.json")
+	err = recorder.Fixture().Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the client asks for status once, to learn which components the daemon runs
+	if len(fixture.Interactions) != 5 {
+		t.Fatalf("expected 5 interactions, got %d", len(fixture.Interactions))
+	}
+
+	server := NewReplayServer(fixture)
+	defer server.Close()
+	client = jsonrpc.NewClient(server.URL)
+
+	if unused := server.Unused(); len(unused) != 5 {
+		t.Errorf("expected all interactions to be unused, got %d", len(unused))
+	}
+	replayedBalance, err := client.AccountBalance(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayedBalance.Available != balance.Available {
+		t.Errorf("expected balance %s, got %s", balance.Available, replayedBalance.Available)
+	}
+	for _, expected := range []string{string(*first), string(*second), string(*second)} {
+		address, err := client.AddressUnused(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(*address) != expected {
+			t.Errorf("expected %s, got %s", expected, *address)
+		}
+	}
+	_, err = client.Version()
+	if err == nil {
+		t.Error("expected the recorded error for version")
+	}
+	if unused := server.Unused(); len(unused) != 0 {
+		t.Errorf("expected every interaction to be replayed, got %+v", unused)
+	}
+
+	_, err = client.SettingsGet()
+	if err == nil {
+		t.Error("expected an error for a call that was not recorded")
+	}
+}
+
+func TestReplayServer_IgnoreParams(t *testing.T) {
+	fixture := &Fixture{Interactions: []Interaction{{
+		Method: "stream_create",
+		Params: map[string]interface{}{
+			"name":             "video",
+			"file_path":        "/tmp/recorded.mp4",
+			"bid":              "0.010000",
+			"include_protobuf": true,
+			"blocking":         true,
+		},
+		Result: json.RawMessage(`{"txid": "abc"}`),
+	}}}
+	server := NewReplayServer(fixture, "file_path")
+	defer server.Close()
+
+	tx, err := jsonrpc.NewClient(server.URL).StreamCreate("video", "/tmp/other.mp4", 0.01, jsonrpc.StreamCreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Txid != "abc" {
+		t.Errorf("expected the recorded transaction, got %+v", tx)
+	}
+}
+
+func TestRecorder_Redacts(t *testing.T) {
+	recorder := NewRecorder(newDaemon(t).URL)
+	client := jsonrpc.NewClient(recorder.URL).AllowSensitive()
+	secrets, err := client.AccountExportSeed("account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secrets.Seed.Reveal() != "abandon abandon abandon" {
+		t.Error("the client should get the real seed while recording")
+	}
+	_, err = client.CallNoDecode("channel_export", map[string]interface{}{"channel_id": "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlocked, err := client.CallNoDecode("wallet_unlock", map[string]interface{}{"password": "hunter2"})
+	if err != nil || unlocked != true {
+		t.Fatalf("expected the wallet to unlock, got %v, %v", unlocked, err)
+	}
+	recorder.Close()
+
+	fixture := recorder.Fixture()
+	encoded, err := json.Marshal(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"abandon", "xprv", "base58 export", "hunter2"} {
+		if strings.Contains(string(encoded), secret) {
+			t.Errorf("fixture holds %q: %s", secret, encoded)
+		}
+	}
+	if !strings.Contains(string(encoded), "xpub") {
+		t.Error("fields that are not sensitive should be kept")
+	}
+
+	server := NewReplayServer(fixture)
+	defer server.Close()
+	client = jsonrpc.NewClient(server.URL).AllowSensitive()
+	unlocked, err = client.CallNoDecode("wallet_unlock", map[string]interface{}{"password": "other"})
+	if err != nil || unlocked != true {
+		t.Errorf("expected the recorded call to match whatever the password, got %v, %v", unlocked, err)
+	}
+	secrets, err = client.AccountExportSeed("account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secrets.Seed.Reveal() != redacted {
+		t.Errorf("expected the redacted seed, got %s", secrets.Seed.Reveal())
+	}
+}
@@ -0,0 +1,210 @@
+package jsonrpc
+
+import (
+	"context"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// ListAllPageSize is the page size the *All iterators request from the daemon
+var ListAllPageSize uint64 = 50
+
+// pageFetcher fetches one page of a list call, hands its items to the caller, and returns the total number of pages
+// and the number of items on the page. The total is 0 if the daemon did not count the pages, e.g. for no_totals.
+type pageFetcher func(c *Client, page uint64) (totalPages uint64, items int, err error)
+
+// paginate calls fetch for each page in turn until the last page has been fetched or ctx is done. Without a page
+// total, the last page is the first one with fewer items than were asked for.
+func (d *Client) paginate(ctx context.Context, fetch pageFetcher) error {
+	c := d.WithContext(ctx)
+	for page := uint64(1); ; page++ {
+		if err := ctx.Err(); err != nil {
+			return errors.Err(err)
+		}
+		totalPages, items, err := fetch(c, page)
+		if err != nil {
+			return err
+		}
+		if totalPages == 0 && uint64(items) < ListAllPageSize || totalPages != 0 && page >= totalPages {
+			return nil
+		}
+	}
+}
+
+// listAll runs paginate in the background. done is called once all pages have been fetched, so the caller can close
+// its item channel. The returned channel gets at most one error and is closed after done is called.
+//
+// The *All iterators built on it are used like this:
+//
+//	claims, errs := d.ClaimListAll(ctx, nil)
+//	for claim := range claims {
+//		...
+//	}
+//	if err := <-errs; err != nil {
+//		...
+//	}
+//
+// Stop reading early by canceling ctx.
+func (d *Client) listAll(ctx context.Context, done func(), fetch pageFetcher) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer done()
+		if err := d.paginate(ctx, fetch); err != nil {
+			errs <- err
+		}
+	}()
+	return errs
+}
+
+// ClaimListAll sends every claim in the account, fetching further pages as the claims are read
+func (d *Client) ClaimListAll(ctx context.Context, account *string) (<-chan Claim, <-chan error) {
+	claims := make(chan Claim)
+	errs := d.listAll(ctx, func() { close(claims) }, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.ClaimList(account, page, ListAllPageSize)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Claims), sendClaims(ctx, claims, response.Claims)
+	})
+	return claims, errs
+}
+
+// StreamListAll sends every stream in the account, fetching further pages as the streams are read
+func (d *Client) StreamListAll(ctx context.Context, account *string) (<-chan Claim, <-chan error) {
+	streams := make(chan Claim)
+	errs := d.listAll(ctx, func() { close(streams) }, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.StreamList(account, page, ListAllPageSize)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Items), sendClaims(ctx, streams, response.Items)
+	})
+	return streams, errs
+}
+
+// SupportListAll sends every support in the account, fetching further pages as the supports are read
+func (d *Client) SupportListAll(ctx context.Context, account *string) (<-chan Claim, <-chan error) {
+	supports := make(chan Claim)
+	errs := d.listAll(ctx, func() { close(supports) }, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.SupportList(account, page, ListAllPageSize)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Items), sendClaims(ctx, supports, response.Items)
+	})
+	return supports, errs
+}
+
+// ClaimSearchAll sends every claim matching the search. The page and page size in options are ignored. With NoTotals
+// set, pages are fetched until one comes back short.
+func (d *Client) ClaimSearchAll(ctx context.Context, options ClaimSearchOptions) (<-chan Claim, <-chan error) {
+	claims := make(chan Claim)
+	errs := d.listAll(ctx, func() { close(claims) }, func(c *Client, page uint64) (uint64, int, error) {
+		options.Page, options.PageSize = page, ListAllPageSize
+		response, err := c.ClaimSearchWithOptions(options)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Claims), sendClaims(ctx, claims, response.Claims)
+	})
+	return claims, errs
+}
+
+func sendClaims(ctx context.Context, ch chan<- Claim, claims []Claim) error {
+	for _, claim := range claims {
+		select {
+		case ch <- claim:
+		case <-ctx.Done():
+			return errors.Err(ctx.Err())
+		}
+	}
+	return nil
+}
+
+// ChannelListAll sends every channel in the account, fetching further pages as the channels are read
+func (d *Client) ChannelListAll(ctx context.Context, account *string, walletID *string) (<-chan Transaction, <-chan error) {
+	channels := make(chan Transaction)
+	errs := d.listAll(ctx, func() { close(channels) }, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.ChannelList(account, page, ListAllPageSize, walletID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Items), sendTransactions(ctx, channels, response.Items)
+	})
+	return channels, errs
+}
+
+// TxoListAll sends every output matching the filters. The page and page size in options are ignored. With NoTotals
+// set, pages are fetched until one comes back short.
+func (d *Client) TxoListAll(ctx context.Context, options TxoListOptions) (<-chan Transaction, <-chan error) {
+	txos := make(chan Transaction)
+	errs := d.listAll(ctx, func() { close(txos) }, func(c *Client, page uint64) (uint64, int, error) {
+		options.Page, options.PageSize = page, ListAllPageSize
+		response, err := c.TxoList(options)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Items), sendTransactions(ctx, txos, response.Items)
+	})
+	return txos, errs
+}
+
+func sendTransactions(ctx context.Context, ch chan<- Transaction, txs []Transaction) error {
+	for _, tx := range txs {
+		select {
+		case ch <- tx:
+		case <-ctx.Done():
+			return errors.Err(ctx.Err())
+		}
+	}
+	return nil
+}
+
+// AccountListAll sends every account in the default wallet, fetching further pages as the accounts are read
+func (d *Client) AccountListAll(ctx context.Context) (<-chan Account, <-chan error) {
+	accounts := make(chan Account)
+	errs := d.listAll(ctx, func() { close(accounts) }, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.AccountList(page, ListAllPageSize)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Items), sendAccounts(ctx, accounts, response.Items)
+	})
+	return accounts, errs
+}
+
+func sendAccounts(ctx context.Context, ch chan<- Account, accounts []Account) error {
+	for _, account := range accounts {
+		select {
+		case ch <- account:
+		case <-ctx.Done():
+			return errors.Err(ctx.Err())
+		}
+	}
+	return nil
+}
+
+// FileListAll sends every file the daemon manages, fetching further pages as the files are read
+func (d *Client) FileListAll(ctx context.Context) (<-chan File, <-chan error) {
+	files := make(chan File)
+	errs := d.listAll(ctx, func() { close(files) }, func(c *Client, page uint64) (uint64, int, error) {
+		response, err := c.FileList(page, ListAllPageSize)
+		if err != nil {
+			return 0, 0, err
+		}
+		return response.TotalPages, len(response.Items), sendFiles(ctx, files, response.Items)
+	})
+	return files, errs
+}
+
+func sendFiles(ctx context.Context, ch chan<- File, files []File) error {
+	for _, file := range files {
+		select {
+		case ch <- file:
+		case <-ctx.Done():
+			return errors.Err(ctx.Err())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package jsonrpc
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/lbryio/lbry.go/v2/extras/util"
+)
+
+func TestClient_ClaimListAll(t *testing.T) {
+	ListAllPageSize = 2
+	defer func() { ListAllPageSize = 50 }()
+
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"claim_list": func(params map[string]interface{}) (interface{}, error) {
+			page := int(params["page"].(float64))
+			var items []interface{}
+			for i := 0; i < 2 && (page-1)*2+i < 5; i++ {
+				items = append(items, map[string]interface{}{"claim_id": strconv.Itoa((page-1)*2 + i)})
+			}
+			return map[string]interface{}{"items": items, "page": page, "page_size": 2, "total_pages": 3}, nil
+		},
+	})
+
+	claims, errs := NewClient(daemon.URL).ClaimListAll(context.Background(), nil)
+	var ids []string
+	for claim := range claims {
+		ids = append(ids, claim.ClaimID)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 5 || ids[4] != "4" {
+		t.Errorf("expected claims 0 to 4, got %v", ids)
+	}
+	if daemon.Calls("claim_list") != 3 {
+		t.Errorf("expected 3 pages to be fetched, got %d", daemon.Calls("claim_list"))
+	}
+}
+
+func TestClient_ClaimListAll_Cancel(t *testing.T) {
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"claim_list": func(params map[string]interface{}) (interface{}, error) {
+			items := []interface{}{map[string]interface{}{"claim_id": "a"}, map[string]interface{}{"claim_id": "b"}}
+			return map[string]interface{}{"items": items, "page": params["page"], "page_size": 2, "total_pages": 100}, nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	claims, errs := NewClient(daemon.URL).ClaimListAll(ctx, nil)
+	<-claims
+	cancel()
+	for range claims {
+	}
+	if err := <-errs; err == nil {
+		t.Error("expected a context error")
+	}
+	if daemon.Calls("claim_list") >= 100 {
+		t.Error("expected paging to stop when the context was canceled")
+	}
+}
+
+func TestClient_ClaimSearchAll_NoTotals(t *testing.T) {
+	ListAllPageSize = 2
+	defer func() { ListAllPageSize = 50 }()
+
+	daemon := newFakeDaemon(t, map[string]fakeHandler{
+		"claim_search": func(params map[string]interface{}) (interface{}, error) {
+			if params["no_totals"] != true {
+				t.Errorf("expected no_totals to be sent, got %v", params)
+			}
+			page := int(params["page"].(float64))
+			var items []interface{}
+			for i := 0; i < 2 && (page-1)*2+i < 5; i++ {
+				items = append(items, map[string]interface{}{"claim_id": strconv.Itoa((page-1)*2 + i)})
+			}
+			// like the daemon, leave out the totals when asked to
+			return map[string]interface{}{"items": items, "page": page, "page_size": 2}, nil
+		},
+	})
+
+	claims, errs := NewClient(daemon.URL).ClaimSearchAll(context.Background(), ClaimSearchOptions{NoTotals: util.PtrToBool(true)})
+	var ids []string
+	for claim := range claims {
+		ids = append(ids, claim.ClaimID)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 5 || ids[4] != "4" {
+		t.Errorf("expected claims 0 to 4, got %v", ids)
+	}
+	if daemon.Calls("claim_search") != 3 {
+		t.Errorf("expected paging to stop at the short page, got %d calls", daemon.Calls("claim_search"))
+	}
+}
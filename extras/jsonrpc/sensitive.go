@@ -0,0 +1,158 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// ErrSensitiveNotAllowed is returned by calls that export seeds or private keys from a client not made with
+// AllowSensitive
+var ErrSensitiveNotAllowed = errors.Base("client is not allowed to export seeds or private keys")
+
+// sensitiveParams are params whose values are never logged
+var sensitiveParams = map[string]bool{
+	"seed":         true,
+	"private_key":  true,
+	"channel_data": true,
+	"password":     true,
+}
+
+// sensitiveMethods are the methods that return seeds or private keys. A method mapped to a param only does when that
+// param is true.
+var sensitiveMethods = map[string]string{
+	"account_list":   "show_seed",
+	"channel_export": "",
+}
+
+// IsSensitiveParam returns true if params, or fields of results, with this name hold seeds, private keys or passwords
+func IsSensitiveParam(name string) bool {
+	return sensitiveParams[name]
+}
+
+// IsSensitiveCall returns true if the call returns seeds or private keys
+func IsSensitiveCall(method string, params map[string]interface{}) bool {
+	param, ok := sensitiveMethods[method]
+	if !ok || param == "" {
+		return ok
+	}
+	switch v := params[param].(type) {
+	case bool:
+		return v
+	case *bool:
+		return v != nil && *v
+	}
+	return false
+}
+
+// checkSensitive returns ErrSensitiveNotAllowed if the call would return seeds or private keys and the client is not
+// made with AllowSensitive
+func (d *Client) checkSensitive(method string, params map[string]interface{}) error {
+	if d.allowSensitive || !IsSensitiveCall(method, params) {
+		return nil
+	}
+	return errors.Prefix(method, ErrSensitiveNotAllowed)
+}
+
+// Secret is a seed or private key. It prints as [redacted], so it doesn't end up in logs by accident. Use Reveal to
+// get the value.
+type Secret string
+
+func (s Secret) String() string {
+	return "[redacted]"
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// Reveal returns the secret value
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// AllowSensitive returns a copy of the client that may export seeds and private keys. The client it is called on still
+// can't, so only the code doing a backup or a channel handoff needs to hold the copy. Other clients reject any call that
+// would return them, including raw calls through Call and CallNoDecode.
+func (d *Client) AllowSensitive() *Client {
+	c := d.clone()
+	c.allowSensitive = true
+	return c
+}
+
+// AccountSecrets holds what is needed to restore an account
+type AccountSecrets struct {
+	AccountID string
+	PublicKey string
+	// Seed is empty for accounts created from a private key
+	Seed       Secret
+	PrivateKey Secret
+}
+
+// AccountExportSeed returns the seed and private key of the account. The client must be made with AllowSensitive.
+func (d *Client) AccountExportSeed(accountID string) (*AccountSecrets, error) {
+	response := new(AccountListResponse)
+	err := d.Call(response, "account_list", map[string]interface{}{
+		"account_id": accountID,
+		"show_seed":  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Items) == 0 {
+		return nil, errors.Err("account %s not found", accountID)
+	}
+	account := response.Items[0]
+	if account.Seed == nil && account.PrivateKey == nil {
+		return nil, errors.Err("daemon returned no seed or private key for account %s", accountID)
+	}
+	secrets := &AccountSecrets{AccountID: account.ID, PublicKey: account.PublicKey}
+	if account.Seed != nil {
+		secrets.Seed = *account.Seed
+	}
+	if account.PrivateKey != nil {
+		secrets.PrivateKey = *account.PrivateKey
+	}
+	return secrets, nil
+}
+
+// ExportedChannel is a channel exported by channel_export, decoded
+type ExportedChannel struct {
+	Name             string `json:"name"`
+	ClaimID          string `json:"claim_id"`
+	HoldingAddress   string `json:"holding_address"`
+	HoldingPublicKey string `json:"holding_public_key"`
+	// SigningPrivateKey is the PEM encoded key that signs the channel's claims
+	SigningPrivateKey Secret `json:"signing_private_key"`
+	// Data is the export as returned by the daemon, for passing to ChannelImport
+	Data Secret `json:"-"`
+}
+
+// ChannelExportKey exports the channel's signing key, so the channel can be imported into another wallet. The client
+// must be made with AllowSensitive.
+func (d *Client) ChannelExportKey(channelClaimID string, accountID *string) (*ExportedChannel, error) {
+	var data string
+	err := d.Call(&data, "channel_export", map[string]interface{}{
+		"channel_id": channelClaimID,
+		"account_id": accountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the export is json encoded as base58
+	decoded := base58.Decode(data)
+	if len(decoded) == 0 {
+		return nil, errors.Err("channel export for %s is not base58", channelClaimID)
+	}
+	exported := &ExportedChannel{}
+	err = json.Unmarshal(decoded, exported)
+	if err != nil {
+		// the error could quote the key, so leave it out
+		return nil, errors.Err("channel export for %s could not be decoded", channelClaimID)
+	}
+	exported.Data = Secret(data)
+	return exported, nil
+}
@@ -0,0 +1,131 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxKeyFee is the most the daemon pays for a stream without asking
+type MaxKeyFee struct {
+	Currency Currency        `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// Settings are the daemon settings clients most often need. SettingsGet and SettingsSet reach the others by key.
+type Settings struct {
+	// MaxKeyFee is nil if the daemon pays any fee
+	MaxKeyFee        *MaxKeyFee `json:"max_key_fee"`
+	ShareUsageData   bool       `json:"share_usage_data"`
+	DataDir          string     `json:"data_dir"`
+	DownloadDir      string     `json:"download_dir"`
+	WalletDir        string     `json:"wallet_dir"`
+	Wallets          []string   `json:"wallets"`
+	SaveFiles        bool       `json:"save_files"`
+	SaveBlobs        bool       `json:"save_blobs"`
+	UseUPnP          bool       `json:"use_upnp"`
+	ComponentsToSkip []string   `json:"components_to_skip"`
+	LbryumServers    []string   `json:"lbryum_servers"`
+	BlockchainName   string     `json:"blockchain_name"`
+	StreamingServer  string     `json:"streaming_server"`
+	TCPPort          uint64     `json:"tcp_port"`
+	UDPPort          uint64     `json:"udp_port"`
+}
+
+// SyncSettings returns the settings a daemon that publishes unattended should run with: it must not pay for streams, and
+// must not report usage data. The map is new on every call, so the deployment's wallet and download directories can be
+// added to it before passing it to EnsureSettings.
+func SyncSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"max_key_fee":      nil,
+		"share_usage_data": false,
+	}
+}
+
+func (d *Client) SettingsGet() (*Settings, error) {
+	response := new(Settings)
+	return response, d.Call(response, "settings_get", map[string]interface{}{})
+}
+
+// SettingsSet sets one setting. The value is sent as json, so it should have the setting's type, e.g. nil to turn off
+// max_key_fee. The daemon saves the setting in its config file, but some settings only take effect after a restart.
+func (d *Client) SettingsSet(key string, value interface{}) error {
+	_, err := d.CallNoDecode("settings_set", map[string]interface{}{
+		"key":   key,
+		"value": value,
+	})
+	return err
+}
+
+// SettingDrift is a setting whose value differs from the required one
+type SettingDrift struct {
+	Key      string
+	Required interface{}
+	Current  interface{}
+	// Fixed is true if EnsureSettings set the required value
+	Fixed bool
+}
+
+// EnsureSettings compares the daemon's settings with the required ones, keyed by setting name, and returns the ones
+// that differ. If fix is true, each of them is set to the required value. Values are compared by their json encoding,
+// so 50 and 50.0 are the same.
+func (d *Client) EnsureSettings(required map[string]interface{}, fix bool) ([]SettingDrift, error) {
+	result, err := d.CallNoDecode("settings_get", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	current, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, errors.Err("settings_get returned %T instead of an object", result)
+	}
+
+	keys := make([]string, 0, len(required))
+	for key := range required {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var drift []SettingDrift
+	for _, key := range keys {
+		value, ok := current[key]
+		if !ok {
+			return drift, errors.Err("daemon has no setting %s", key)
+		}
+		same, err := sameJSON(value, required[key])
+		if err != nil {
+			return drift, errors.Prefix("setting "+key, err)
+		}
+		if same {
+			continue
+		}
+		drift = append(drift, SettingDrift{Key: key, Required: required[key], Current: value})
+		if fix {
+			err = d.SettingsSet(key, required[key])
+			if err != nil {
+				return drift, errors.Prefix("setting "+key, err)
+			}
+			drift[len(drift)-1].Fixed = true
+		}
+	}
+	return drift, nil
+}
+
+// sameJSON returns true if a and b encode to the same json value, regardless of key order or number formatting
+func sameJSON(a, b interface{}) (bool, error) {
+	var values [2]interface{}
+	for i, v := range []interface{}{a, b} {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return false, errors.Err(err)
+		}
+		err = json.Unmarshal(encoded, &values[i])
+		if err != nil {
+			return false, errors.Err(err)
+		}
+	}
+	return reflect.DeepEqual(values[0], values[1]), nil
+}
@@ -0,0 +1,91 @@
+package jsonrpc
+
+import "time"
+
+// MethodClass groups daemon methods by how long they are expected to take, so each group can get its own timeout
+type MethodClass int
+
+const (
+	// MethodClassRead is for fast lookups such as resolve, balances and lists
+	MethodClassRead MethodClass = iota
+	// MethodClassWrite is for transactions that don't upload anything, such as supports, updates and abandons
+	MethodClassWrite
+	// MethodClassPublish is for slow calls that move whole files, such as stream_create and get
+	MethodClassPublish
+)
+
+var methodClasses = []MethodClass{MethodClassRead, MethodClassWrite, MethodClassPublish}
+
+// DefaultTimeouts are used by NewClient for each method class
+var DefaultTimeouts = map[MethodClass]time.Duration{
+	MethodClassRead:    1 * time.Minute,
+	MethodClassWrite:   5 * time.Minute,
+	MethodClassPublish: 40 * time.Minute,
+}
+
+var readMethods = map[string]bool{
+	"account_balance":    true,
+	"account_list":       true,
+	"address_list":       true,
+	"address_unused":     true,
+	"channel_list":       true,
+	"claim_list":         true,
+	"claim_search":       true,
+	"collection_list":    true,
+	"collection_resolve": true,
+	"ffmpeg_find":        true,
+	"file_list":          true,
+	"purchase_list":      true,
+	"resolve":            true,
+	"settings_get":       true,
+	"status":             true,
+	"stream_list":        true,
+	"support_list":       true,
+	"sync_hash":          true,
+	"transaction_list":   true,
+	"transaction_show":   true,
+	"txo_list":           true,
+	"utxo_list":          true,
+	"version":            true,
+	"wallet_balance":     true,
+	"wallet_list":        true,
+}
+
+var publishMethods = map[string]bool{
+	"channel_create": true,
+	"get":            true,
+	"publish":        true,
+	"stream_create":  true,
+}
+
+// ClassOf returns the method class used to pick the timeout for a daemon method. Methods that are not known to be
+// reads or publishes are treated as writes.
+func ClassOf(method string) MethodClass {
+	if readMethods[method] {
+		return MethodClassRead
+	}
+	if publishMethods[method] {
+		return MethodClassPublish
+	}
+	return MethodClassWrite
+}
+
+// SetTimeout sets the timeout for all calls in the given method class. A timeout of 0 means no timeout.
+func (d *Client) SetTimeout(class MethodClass, timeout time.Duration) {
+	d.timeouts[class] = timeout
+}
+
+// Timeout returns the timeout currently used for the given method class
+func (d *Client) Timeout(class MethodClass) time.Duration {
+	return d.timeouts[class]
+}
+
+// WithTimeout returns a copy of the client that uses the given timeout for every call, regardless of method class.
+// Use it to override the timeout of a single call: d.WithTimeout(time.Hour).StreamCreate(...)
+func (d *Client) WithTimeout(timeout time.Duration) *Client {
+	c := d.clone()
+	for _, class := range methodClasses {
+		c.timeouts[class] = timeout
+	}
+	return c
+}
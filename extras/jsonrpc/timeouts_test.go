@@ -0,0 +1,90 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/stop"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := map[string]MethodClass{
+		"resolve":         MethodClassRead,
+		"account_balance": MethodClassRead,
+		"support_create":  MethodClassWrite,
+		"stream_update":   MethodClassWrite,
+		"stream_create":   MethodClassPublish,
+		"some_new_method": MethodClassWrite,
+	}
+	for method, expected := range tests {
+		if got := ClassOf(method); got != expected {
+			t.Errorf("%s: expected class %d, got %d", method, expected, got)
+		}
+	}
+}
+
+func TestClient_Timeouts(t *testing.T) {
+	d := NewClient("")
+	for class, timeout := range DefaultTimeouts {
+		if d.Timeout(class) != timeout {
+			t.Errorf("class %d: expected default timeout %s, got %s", class, timeout, d.Timeout(class))
+		}
+	}
+
+	d.SetTimeout(MethodClassRead, time.Second)
+	if d.Timeout(MethodClassRead) != time.Second {
+		t.Errorf("expected read timeout to be updated, got %s", d.Timeout(MethodClassRead))
+	}
+	if d.Timeout(MethodClassPublish) != DefaultTimeouts[MethodClassPublish] {
+		t.Error("setting the read timeout should not change the publish timeout")
+	}
+}
+
+func TestClient_WithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":{"version":"test"}}`))
+	}))
+	defer server.Close()
+
+	d := NewClient(server.URL)
+	_, err := d.WithTimeout(10 * time.Millisecond).Version()
+	if err == nil {
+		t.Error("expected the per-call timeout to be exceeded")
+	}
+	if d.Timeout(MethodClassRead) != DefaultTimeouts[MethodClassRead] {
+		t.Error("WithTimeout should not modify the original client")
+	}
+
+	_, err = d.Version()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_WithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	grp := stop.New()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		grp.Stop()
+	}()
+
+	start := time.Now()
+	_, err := NewClient(server.URL).WithContext(grp.Context()).Version()
+	if err == nil {
+		t.Fatal("expected the call to be canceled")
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("call was not aborted when the stop group stopped")
+	}
+}
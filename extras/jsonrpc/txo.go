@@ -0,0 +1,74 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/lbrycrd"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ClaimOutput is a transaction output that creates, updates or supports a claim
+type ClaimOutput struct {
+	Nout    uint64
+	Amount  Dewies
+	Op      lbrycrd.ClaimOp
+	Name    string
+	ClaimID string
+	Value   []byte
+	// Err is set if the output starts with a claim opcode but the rest of its script is malformed. Only Nout and
+	// Amount are filled in then.
+	Err error
+}
+
+// ClaimOutputs decodes the raw transaction and returns the claim operation carried by each of its claim outputs. Unlike
+// the claim fields on Outputs, which the daemon only fills in for some calls, this works for any transaction the daemon
+// returns with its hex, so spends can be attributed to claims without looking them up again. A malformed claim output
+// does not keep the others from being decoded; it is returned with Err set.
+func (t *TransactionSummary) ClaimOutputs() ([]ClaimOutput, error) {
+	raw, err := hex.DecodeString(t.Hex)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	err = tx.Deserialize(bytes.NewReader(raw))
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	txid := tx.TxHash().String()
+
+	var outputs []ClaimOutput
+	for nout, out := range tx.TxOut {
+		cs, err := lbrycrd.ParseClaimScript(out.PkScript)
+		if err != nil {
+			outputs = append(outputs, ClaimOutput{
+				Nout:   uint64(nout),
+				Amount: Dewies(out.Value),
+				Err:    errors.Prefix(fmt.Sprintf("output %s:%d", txid, nout), err),
+			})
+			continue
+		}
+		if cs.Op == lbrycrd.ClaimOpNone {
+			continue
+		}
+		claimID := cs.ClaimID
+		if cs.Op == lbrycrd.ClaimOpCreate {
+			claimID, err = lbrycrd.ClaimIDFromOutpoint(txid, nout)
+			if err != nil {
+				return nil, errors.Err(err)
+			}
+		}
+		outputs = append(outputs, ClaimOutput{
+			Nout:    uint64(nout),
+			Amount:  Dewies(out.Value),
+			Op:      cs.Op,
+			Name:    cs.Name,
+			ClaimID: claimID,
+			Value:   cs.Value,
+		})
+	}
+	return outputs, nil
+}
@@ -0,0 +1,62 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbry.go/v2/lbrycrd"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestTransactionSummary_ClaimOutputs(t *testing.T) {
+	claimID := "589bc4845caca70977332025990b2a1807732b44"
+	claimIDBytes, _ := hex.DecodeString(claimID)
+	for i, j := 0, len(claimIDBytes)-1; i < j; i, j = i+1, j-1 {
+		claimIDBytes[i], claimIDBytes[j] = claimIDBytes[j], claimIDBytes[i]
+	}
+
+	create, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_NOP6).AddData([]byte("new")).AddData([]byte("value")).
+		AddOp(txscript.OP_2DROP).AddOp(txscript.OP_DROP).AddOp(txscript.OP_TRUE).Script()
+	support, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_NOP7).AddData([]byte("old")).AddData(claimIDBytes).
+		AddOp(txscript.OP_2DROP).AddOp(txscript.OP_DROP).AddOp(txscript.OP_TRUE).Script()
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(100000000, create))
+	tx.AddTxOut(wire.NewTxOut(5000, []byte{txscript.OP_TRUE}))
+	tx.AddTxOut(wire.NewTxOut(1000, create[:5]))
+	tx.AddTxOut(wire.NewTxOut(2500, support))
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := TransactionSummary{Hex: hex.EncodeToString(buf.Bytes())}
+	outputs, err := summary.ClaimOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 3 {
+		t.Fatalf("expected 3 claim outputs, got %d", len(outputs))
+	}
+
+	expectedID, err := lbrycrd.ClaimIDFromOutpoint(tx.TxHash().String(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := outputs[0]; o.Nout != 0 || o.Op != lbrycrd.ClaimOpCreate || o.Name != "new" || o.ClaimID != expectedID ||
+		o.Amount != DewiesPerLBC || string(o.Value) != "value" {
+		t.Errorf("unexpected create output %+v", o)
+	}
+	if o := outputs[1]; o.Nout != 2 || o.Err == nil || !strings.Contains(o.Err.Error(), ":2") {
+		t.Errorf("expected the malformed output to have an error naming it, got %+v", o)
+	}
+	if o := outputs[2]; o.Nout != 3 || o.Op != lbrycrd.ClaimOpSupport || o.Name != "old" || o.ClaimID != claimID ||
+		o.Amount != 2500 {
+		t.Errorf("unexpected support output %+v", o)
+	}
+}
@@ -0,0 +1,161 @@
+package jsonrpc
+
+import (
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	schema "github.com/lbryio/lbry.go/v2/schema/stake"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/golang/protobuf/proto"
+	lbryschema "github.com/lbryio/types/v2/go"
+	"github.com/shopspring/decimal"
+)
+
+// StreamUpdateChanges updates a stream with only the options that change it. The current claim is fetched first, and
+// metadata options that match what the claim already has are dropped, so the daemon's merge keeps everything else, such
+// as the thumbnail and fee a creator set by hand. Tags given in the options replace the current tags instead of being
+// added to them.
+//
+// It returns nil and no error when the update would not change the claim, so no transaction is made.
+func (d *Client) StreamUpdateChanges(claimID string, options StreamUpdateOptions) (*TransactionSummary, error) {
+	search, err := d.ClaimSearch(nil, &claimID, nil, nil, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(search.Claims) == 0 {
+		return nil, errors.Err("claim %s not found", claimID)
+	}
+	current := &search.Claims[0].Value
+	if current.GetStream() == nil {
+		return nil, errors.Err("claim %s is not a stream", claimID)
+	}
+
+	desired := proto.Clone(current).(*lbryschema.Claim)
+	err = applyStreamOptions(desired, options.StreamCreateOptions)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := schema.Diff(&schema.StakeHelper{Claim: current}, &schema.StakeHelper{Claim: desired})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := options
+	if options.StreamCreateOptions != nil {
+		c := *options.StreamCreateOptions
+		changes.StreamCreateOptions = &c
+		if !diff.Changed("title") {
+			c.Title = nil
+		}
+		if !diff.Changed("description") {
+			c.Description = nil
+		}
+		if !diff.Changed("thumbnail") {
+			c.ThumbnailURL = nil
+		}
+		if diff.Changed("tags") {
+			clear := true
+			changes.ClearTags = &clear
+		} else {
+			c.Tags = nil
+		}
+		if !diff.Changed("stream.author") {
+			c.Author = nil
+		}
+		if !diff.Changed("stream.license") {
+			c.License = nil
+		}
+		if !diff.Changed("stream.license_url") {
+			c.LicenseURL = nil
+		}
+		if !diff.Changed("stream.release_time") {
+			c.ReleaseTime = nil
+		}
+		if !diff.Changed("stream.fee") {
+			c.Fee = nil
+		}
+	}
+	if !diff.NeedsUpdate() && !changes.changesMoreThanMetadata() {
+		return nil, nil
+	}
+
+	replace := false
+	changes.Replace = &replace
+	return d.StreamUpdate(claimID, changes)
+}
+
+// changesMoreThanMetadata returns true if the options change something applyStreamOptions doesn't compare, such as the
+// file, the bid or the channel
+func (o StreamUpdateOptions) changesMoreThanMetadata() bool {
+	if o.Name != nil || o.FilePath != nil || o.FileSize != nil || o.Bid != nil ||
+		o.ClearLanguages != nil || o.ClearLocations != nil {
+		return true
+	}
+	c := o.StreamCreateOptions
+	if c == nil {
+		return false
+	}
+	return len(c.Languages) > 0 || len(c.Locations) > 0 || c.ClaimAddress != nil || c.StreamType != nil ||
+		c.Duration != nil || c.Width != nil || c.Height != nil || c.ChannelName != nil || c.ChannelID != nil
+}
+
+// applyStreamOptions sets the metadata from the options on the claim, the way the daemon would
+func applyStreamOptions(claim *lbryschema.Claim, options *StreamCreateOptions) error {
+	if options == nil {
+		return nil
+	}
+	stream := claim.GetStream()
+	if options.Title != nil {
+		claim.Title = *options.Title
+	}
+	if options.Description != nil {
+		claim.Description = *options.Description
+	}
+	if options.ThumbnailURL != nil {
+		claim.Thumbnail = &lbryschema.Source{Url: *options.ThumbnailURL}
+	}
+	if options.Tags != nil {
+		claim.Tags = options.Tags
+	}
+	if options.Author != nil {
+		stream.Author = *options.Author
+	}
+	if options.License != nil {
+		stream.License = *options.License
+	}
+	if options.LicenseURL != nil {
+		stream.LicenseUrl = *options.LicenseURL
+	}
+	if options.ReleaseTime != nil {
+		stream.ReleaseTime = *options.ReleaseTime
+	}
+	if options.Fee != nil {
+		fee, err := feeProto(options.Fee, stream.GetFee())
+		if err != nil {
+			return err
+		}
+		stream.Fee = fee
+	}
+	return nil
+}
+
+// feeProto converts a fee option to the protobuf fee. The address of the current fee is kept if the option has none.
+func feeProto(fee *Fee, current *lbryschema.Fee) (*lbryschema.Fee, error) {
+	currency, ok := lbryschema.Fee_Currency_value[string(fee.FeeCurrency)]
+	if !ok {
+		return nil, errors.Err("unknown fee currency %q", fee.FeeCurrency)
+	}
+	// LBC and BTC fees are stored in their smallest unit, USD fees in cents
+	unit := decimal.New(1, 8)
+	if fee.FeeCurrency == CurrencyUSD {
+		unit = decimal.New(1, 2)
+	}
+	pbFee := &lbryschema.Fee{
+		Currency: lbryschema.Fee_Currency(currency),
+		Amount:   uint64(fee.FeeAmount.Mul(unit).IntPart()),
+		Address:  current.GetAddress(),
+	}
+	if fee.FeeAddress != nil {
+		pbFee.Address = base58.Decode(*fee.FeeAddress)
+	}
+	return pbFee, nil
+}
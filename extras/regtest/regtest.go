@@ -0,0 +1,340 @@
+/*
+Package regtest runs a throwaway lbrycrd node in regtest mode for integration tests. It funds wallets, mines blocks on
+demand and, when a daemon configured for the same regtest chain is available, publishes and resolves claims end to end.
+
+	func TestPublish(t *testing.T) {
+		h := regtest.New(t, regtest.Config{})
+		_, err := h.FundDaemon(10)
+		...
+		_, err = h.Publish("test", "/path/to/file", 1)
+		...
+	}
+
+Tests are skipped when lbrycrdd is not installed. The daemon helpers need a daemon URL in Config.DaemonURL or in the
+REGTEST_DAEMON_URL environment variable, and are skipped without one.
+*/
+package regtest
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/extras/jsonrpc"
+	"github.com/lbryio/lbry.go/v2/extras/util/retry"
+	"github.com/lbryio/lbry.go/v2/lbrycrd"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	rpcUser     = "regtest"
+	rpcPassword = "regtest"
+	// coinbase outputs can only be spent after 100 confirmations
+	coinbaseMaturity = 101
+)
+
+// Config configures the harness. The zero value runs lbrycrdd from the PATH in a temporary directory.
+type Config struct {
+	// LbrycrdPath is the lbrycrdd binary. Defaults to $LBRYCRDD, then to lbrycrdd on the PATH.
+	LbrycrdPath string
+	// DataDir is where the chain is kept. Defaults to a temporary directory that is removed on Stop.
+	DataDir string
+	// DaemonURL is the address of a daemon that uses this regtest chain. Defaults to $REGTEST_DAEMON_URL.
+	DaemonURL string
+	// StartTimeout is how long to wait for lbrycrd to accept RPC calls. Defaults to 30 seconds.
+	StartTimeout time.Duration
+}
+
+// Harness is a running regtest lbrycrd node and, optionally, a daemon using it
+type Harness struct {
+	Chain  *lbrycrd.Client
+	Daemon *jsonrpc.Client
+	Params chaincfg.Params
+
+	cmd        *exec.Cmd
+	dataDir    string
+	removeData bool
+}
+
+// New starts a harness for the test and stops it when the test finishes. The test is skipped if lbrycrdd is not
+// installed.
+func New(t testing.TB, config Config) *Harness {
+	t.Helper()
+	if _, err := lbrycrdPath(config); err != nil {
+		t.Skip(err)
+	}
+	h, err := Start(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := h.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	return h
+}
+
+// Start starts lbrycrd in regtest mode and mines enough blocks for its wallet to have spendable coins
+func Start(config Config) (*Harness, error) {
+	path, err := lbrycrdPath(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.StartTimeout == 0 {
+		config.StartTimeout = 30 * time.Second
+	}
+	if config.DaemonURL == "" {
+		config.DaemonURL = os.Getenv("REGTEST_DAEMON_URL")
+	}
+
+	h := &Harness{Params: lbrycrd.ChainParamsMap[lbrycrd.LbrycrdRegtest], dataDir: config.DataDir}
+	if h.dataDir == "" {
+		h.dataDir, err = ioutil.TempDir("", "regtest")
+		if err != nil {
+			return nil, errors.Err(err)
+		}
+		h.removeData = true
+	}
+
+	rpcPort, err := freePort()
+	if err != nil {
+		h.cleanup()
+		return nil, err
+	}
+	p2pPort, err := freePort()
+	if err != nil {
+		h.cleanup()
+		return nil, err
+	}
+
+	h.cmd = exec.Command(path,
+		"-regtest",
+		"-server",
+		"-txindex",
+		"-printtoconsole=0",
+		"-datadir="+h.dataDir,
+		"-rpcuser="+rpcUser,
+		"-rpcpassword="+rpcPassword,
+		"-rpcport="+strconv.Itoa(rpcPort),
+		"-port="+strconv.Itoa(p2pPort),
+	)
+	err = h.cmd.Start()
+	if err != nil {
+		h.cleanup()
+		return nil, errors.Err(err)
+	}
+
+	url := "rpc://" + rpcUser + ":" + rpcPassword + "@127.0.0.1:" + strconv.Itoa(rpcPort)
+	ctx, cancel := context.WithTimeout(context.Background(), config.StartTimeout)
+	defer cancel()
+	err = retry.Do(ctx, pollPolicy, func() error {
+		h.Chain, err = lbrycrd.New(url, &h.Params)
+		return err
+	})
+	if err != nil {
+		_ = h.Stop()
+		return nil, errors.Prefix("lbrycrd did not start", err)
+	}
+
+	if config.DaemonURL != "" {
+		h.Daemon = jsonrpc.NewClient(config.DaemonURL)
+	}
+
+	_, err = h.Mine(coinbaseMaturity)
+	if err != nil {
+		_ = h.Stop()
+		return nil, err
+	}
+	return h, nil
+}
+
+// Stop shuts lbrycrd down and removes its data directory if the harness created it
+func (h *Harness) Stop() error {
+	var err error
+	if h.Chain != nil {
+		_, err = h.Chain.RawRequest("stop", nil)
+		h.Chain.Shutdown()
+	}
+	if h.cmd != nil && h.cmd.Process != nil {
+		exited := make(chan struct{})
+		go func() {
+			_ = h.cmd.Wait()
+			close(exited)
+		}()
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			_ = h.cmd.Process.Kill()
+			<-exited
+		}
+	}
+	h.cleanup()
+	return errors.Err(err)
+}
+
+func (h *Harness) cleanup() {
+	if h.removeData {
+		_ = os.RemoveAll(h.dataDir)
+	}
+}
+
+// Mine mines blocks and returns their hashes
+func (h *Harness) Mine(blocks uint32) ([]*chainhash.Hash, error) {
+	hashes, err := h.Chain.Generate(blocks)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return hashes, nil
+}
+
+// Fund sends credits from the lbrycrd wallet to the address and mines a block to confirm the transaction
+func (h *Harness) Fund(address string, amount float64) (*chainhash.Hash, error) {
+	decoded, err := lbrycrd.DecodeAddress(address, &h.Params)
+	if err != nil {
+		return nil, err
+	}
+	lbc, err := btcutil.NewAmount(amount)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	txid, err := h.Chain.SendToAddress(decoded, lbc)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	_, err = h.Mine(1)
+	return txid, err
+}
+
+var errNoDaemon = errors.Base("no daemon configured for this harness")
+
+// FundDaemon sends credits to the daemon's default account and waits until the daemon sees them on top of what it
+// already had
+func (h *Harness) FundDaemon(amount float64) (*chainhash.Hash, error) {
+	if h.Daemon == nil {
+		return nil, errors.Err(errNoDaemon)
+	}
+	before, err := h.Daemon.AccountBalance(nil)
+	if err != nil {
+		return nil, err
+	}
+	address, err := h.Daemon.AddressUnused(nil)
+	if err != nil {
+		return nil, err
+	}
+	txid, err := h.Fund(string(*address), amount)
+	if err != nil {
+		return nil, err
+	}
+	expected := before.Available + jsonrpc.DewiesFromFloat(amount)
+	return txid, h.waitFor(func() (bool, error) {
+		balance, err := h.Daemon.AccountBalance(nil)
+		if err != nil {
+			return false, err
+		}
+		return balance.Available >= expected, nil
+	})
+}
+
+// Publish publishes a stream through the daemon, mines a block and waits until the claim resolves
+func (h *Harness) Publish(name, filePath string, bid float64) (*jsonrpc.Claim, error) {
+	if h.Daemon == nil {
+		return nil, errors.Err(errNoDaemon)
+	}
+	tx, err := h.Daemon.StreamCreate(name, filePath, bid, jsonrpc.StreamCreateOptions{
+		ClaimCreateOptions: jsonrpc.ClaimCreateOptions{Title: &name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Outputs) == 0 {
+		return nil, errors.Err("stream_create returned no outputs")
+	}
+	_, err = h.Mine(1)
+	if err != nil {
+		return nil, err
+	}
+	return h.Resolve(name + "#" + tx.Outputs[0].ClaimID)
+}
+
+// Resolve resolves the url through the daemon, waiting until the claim is confirmed
+func (h *Harness) Resolve(url string) (*jsonrpc.Claim, error) {
+	if h.Daemon == nil {
+		return nil, errors.Err(errNoDaemon)
+	}
+	var claim jsonrpc.Claim
+	err := h.waitFor(func() (bool, error) {
+		response, err := h.Daemon.Resolve(url)
+		if err != nil {
+			return false, err
+		}
+		c, ok := (*response)[url]
+		if !ok || c.ClaimID == "" || c.Confirmations < 1 {
+			return false, nil
+		}
+		claim = c
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+var errNotCaughtUp = errors.Base("timed out waiting for the daemon to catch up with the chain")
+
+// pollPolicy polls every 250 milliseconds until the context is done
+var pollPolicy = retry.Policy{MinInterval: 250 * time.Millisecond, Multiplier: 1}
+
+// waitFor polls done until it returns true, returns an error, or 30 seconds pass
+func (h *Harness) waitFor(done func() (bool, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := retry.Do(ctx, pollPolicy, func() error {
+		ok, err := done()
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		if !ok {
+			return errNotCaughtUp
+		}
+		return nil
+	})
+	if errors.Is(err, errNotCaughtUp) {
+		return errors.Err(errNotCaughtUp)
+	}
+	return err
+}
+
+func lbrycrdPath(config Config) (string, error) {
+	path := config.LbrycrdPath
+	if path == "" {
+		path = os.Getenv("LBRYCRDD")
+	}
+	if path == "" {
+		path = "lbrycrdd"
+	}
+	found, err := exec.LookPath(path)
+	if err != nil {
+		return "", errors.Err("lbrycrdd not found: %v", err)
+	}
+	return found, nil
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, errors.Err(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
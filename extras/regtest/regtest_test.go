@@ -0,0 +1,54 @@
+package regtest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestHarness_Mine(t *testing.T) {
+	h := New(t, Config{})
+
+	before, err := h.Chain.GetBlockCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes, err := h.Mine(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := h.Chain.GetBlockCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 3 || after != before+3 {
+		t.Errorf("expected 3 new blocks, got %d hashes and height %d -> %d", len(hashes), before, after)
+	}
+}
+
+func TestHarness_Publish(t *testing.T) {
+	h := New(t, Config{})
+	if h.Daemon == nil {
+		t.Skip("set REGTEST_DAEMON_URL to run daemon tests")
+	}
+
+	_, err := h.FundDaemon(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "regtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_, _ = f.WriteString("regtest harness")
+	_ = f.Close()
+
+	claim, err := h.Publish("regtest-harness", f.Name(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Name != "regtest-harness" {
+		t.Errorf("resolved the wrong claim: %+v", claim)
+	}
+}
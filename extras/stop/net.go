@@ -0,0 +1,70 @@
+package stop
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ShutdownTimeout is how long a server started with Serve gets to finish requests in flight once the group stops.
+// Connections still open after that are closed.
+var ShutdownTimeout = 10 * time.Second
+
+// ListenAndServe listens on server.Addr and serves in the background like Serve. It returns an error if it can't
+// listen; errors after that are recorded in the group.
+func (s *Group) ListenAndServe(server *http.Server) error {
+	addr := server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.Serve(server, l)
+	return nil
+}
+
+// Serve serves on l in a goroutine tracked by the group, and shuts the server down gracefully when the group stops.
+// An error from the server, other than the one for a normal shutdown, is recorded like the errors from Go.
+func (s *Group) Serve(server *http.Server, l net.Listener) {
+	s.Go(func() error {
+		err := server.Serve(l)
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	})
+	s.Go(func() error {
+		<-s.Ch()
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(ctx)
+		if err == context.DeadlineExceeded {
+			return server.Close()
+		}
+		return err
+	})
+}
+
+// CloseOnStop closes c when the group stops, e.g. to unblock a goroutine waiting in Accept on a net.Listener. An error
+// from Close is recorded like the errors from Go, unless c was already closed.
+func (s *Group) CloseOnStop(c io.Closer) {
+	s.Go(func() error {
+		<-s.Ch()
+		err := c.Close()
+		if err != nil && isClosedError(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// isClosedError returns true for the error net returns when closing something twice. net.ErrClosed is not available
+// before go 1.16.
+func isClosedError(err error) bool {
+	return strings.Contains(err.Error(), "use of closed network connection")
+}
@@ -0,0 +1,83 @@
+package stop
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGroup_Serve(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New()
+	s.Serve(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})}, l)
+
+	resp, err := http.Get("http://" + l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("unexpected response %q", body)
+	}
+
+	if err := s.StopAndWaitTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("a normal shutdown should not record an error, got %v", err)
+	}
+	if _, err := net.Dial("tcp", l.Addr().String()); err == nil {
+		t.Error("expected the listener to be closed")
+	}
+}
+
+func TestGroup_ListenAndServe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	s := New()
+	err = s.ListenAndServe(&http.Server{Addr: l.Addr().String()})
+	if err == nil {
+		t.Error("expected an error listening on an address in use")
+	}
+	s.StopAndWait()
+}
+
+func TestGroup_CloseOnStop(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New()
+	s.CloseOnStop(l)
+
+	accepted := make(chan error)
+	go func() {
+		_, err := l.Accept()
+		accepted <- err
+	}()
+
+	s.StopAndWait()
+	select {
+	case err := <-accepted:
+		if err == nil {
+			t.Error("expected Accept to fail once the listener is closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Accept was not unblocked")
+	}
+	if err := s.Err(); err != nil {
+		t.Error(err)
+	}
+}
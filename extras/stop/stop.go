@@ -16,8 +16,12 @@ package stop
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Chan is a receive-only channel
@@ -31,6 +35,9 @@ type Group struct {
 
 	mu        *sync.Mutex
 	waitingOn map[string]int
+
+	errMu sync.Mutex
+	errs  []error
 }
 type Stopper = Group
 
@@ -59,6 +66,12 @@ func (s *Group) Ch() Chan {
 	return s.ctx.Done()
 }
 
+// Context returns a context that is canceled when Stop is called. Pass it to blocking calls that should be aborted on
+// shutdown, such as jsonrpc.Client.WithContext.
+func (s *Group) Context() context.Context {
+	return s.ctx
+}
+
 // Stop signals any listening processes to stop. After the first call, Stop() does nothing.
 func (s *Group) Stop() {
 	s.cancel()
@@ -70,11 +83,74 @@ func (s *Group) StopAndWait() {
 	s.Wait()
 }
 
-// Child returns a new instance that will be stopped when s is stopped.
+// StopAndWaitTimeout is like StopAndWait but gives up after timeout. It returns an error if some goroutines are still
+// running by then. Groups created with NewDebug name the routines they are still waiting on.
+func (s *Group) StopAndWaitTimeout(timeout time.Duration) error {
+	s.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	if s.waitingOn == nil {
+		return fmt.Errorf("stop group did not stop within %s", timeout)
+	}
+	s.mu.Lock()
+	var waiting []string
+	for name, count := range s.waitingOn {
+		if count > 0 {
+			waiting = append(waiting, fmt.Sprintf("%d %s", count, name))
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(waiting)
+	return fmt.Errorf("stop group did not stop within %s, still waiting on %s", timeout, strings.Join(waiting, ", "))
+}
+
+// Child returns a new instance that will be stopped when s is stopped. Stopping the child does not stop s.
 func (s *Group) Child() *Group {
 	return New(s)
 }
 
+// Go runs f in a goroutine tracked by the group and records the error it returns, if any. The error does not stop the
+// group; call Stop from f if one failure should end the others.
+func (s *Group) Go(f func() error) {
+	s.Add(1)
+	go func() {
+		defer s.Done()
+		if err := f(); err != nil {
+			s.errMu.Lock()
+			s.errs = append(s.errs, err)
+			s.errMu.Unlock()
+		}
+	}()
+}
+
+// Err returns the first error returned by a function started with Go, or nil if none failed
+func (s *Group) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return s.errs[0]
+}
+
+// Errors returns every error returned by functions started with Go, in the order they were returned
+func (s *Group) Errors() []error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return append([]error(nil), s.errs...)
+}
+
 //AddNamed is the same as Add but will register the functional name of the routine for later output. See `DoneNamed`.
 func (s *Group) AddNamed(delta int, name string) {
 	s.Add(delta)
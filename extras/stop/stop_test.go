@@ -0,0 +1,66 @@
+package stop
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup_Child(t *testing.T) {
+	parent := New()
+	child := parent.Child()
+
+	child.Stop()
+	select {
+	case <-parent.Ch():
+		t.Fatal("stopping the child should not stop the parent")
+	default:
+	}
+
+	grandchild := New(parent).Child()
+	parent.Stop()
+	select {
+	case <-grandchild.Ch():
+	case <-time.After(time.Second):
+		t.Fatal("stopping the parent should stop its descendants")
+	}
+}
+
+func TestGroup_Go(t *testing.T) {
+	s := New()
+	first := errors.New("first")
+	second := errors.New("second")
+
+	s.Go(func() error { return first })
+	s.Wait()
+	s.Go(func() error { return nil })
+	s.Go(func() error { return second })
+	s.Wait()
+
+	if s.Err() != first {
+		t.Errorf("expected the first error, got %v", s.Err())
+	}
+	if errs := s.Errors(); len(errs) != 2 || errs[1] != second {
+		t.Errorf("expected both errors, got %v", errs)
+	}
+}
+
+func TestGroup_StopAndWaitTimeout(t *testing.T) {
+	s := New()
+	s.Go(func() error {
+		<-s.Ch()
+		return nil
+	})
+	if err := s.StopAndWaitTimeout(time.Second); err != nil {
+		t.Error(err)
+	}
+
+	s = NewDebug()
+	s.AddNamed(1, "stuck")
+	defer s.DoneNamed("stuck")
+	err := s.StopAndWaitTimeout(10 * time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "1 stuck") {
+		t.Errorf("expected a timeout naming the stuck routine, got %v", err)
+	}
+}
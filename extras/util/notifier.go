@@ -0,0 +1,187 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"github.com/nlopes/slack"
+)
+
+type NotificationLevel string
+
+const (
+	LevelInfo  = NotificationLevel("info")
+	LevelWarn  = NotificationLevel("warn")
+	LevelError = NotificationLevel("error")
+)
+
+// Fields are structured key/value pairs attached to a notification
+type Fields map[string]interface{}
+
+// Notifier sends alerts to wherever a deployment wants them. Use NopNotifier when no alerting is configured.
+type Notifier interface {
+	Info(message string, fields Fields) error
+	Warn(message string, fields Fields) error
+	Error(message string, fields Fields) error
+}
+
+var notifierHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// formatNotification renders a notification as a single line of text, with fields sorted by key
+func formatNotification(message string, fields Fields) string {
+	if len(fields) == 0 {
+		return message
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, fields[k])
+	}
+	return message + " " + strings.Join(pairs, " ")
+}
+
+func postJSON(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Err(err)
+	}
+	resp, err := notifierHTTPClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Err(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.Err("notification webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NopNotifier discards all notifications
+type NopNotifier struct{}
+
+func (NopNotifier) Info(string, Fields) error  { return nil }
+func (NopNotifier) Warn(string, Fields) error  { return nil }
+func (NopNotifier) Error(string, Fields) error { return nil }
+
+// SlackNotifier posts notifications to a slack channel
+type SlackNotifier struct {
+	api      *slack.Client
+	channel  string
+	username string
+}
+
+// NewSlackNotifier creates a notifier that posts to the given channel as username
+func NewSlackNotifier(token, channel, username string) *SlackNotifier {
+	if !strings.HasPrefix(channel, "#") {
+		channel = "#" + channel
+	}
+	return &SlackNotifier{api: slack.New(token), channel: channel, username: username}
+}
+
+func (s *SlackNotifier) Info(message string, fields Fields) error {
+	return s.send(LevelInfo, message, fields)
+}
+
+func (s *SlackNotifier) Warn(message string, fields Fields) error {
+	return s.send(LevelWarn, message, fields)
+}
+
+func (s *SlackNotifier) Error(message string, fields Fields) error {
+	return s.send(LevelError, message, fields)
+}
+
+func (s *SlackNotifier) send(level NotificationLevel, message string, fields Fields) error {
+	return sendToSlack(s.api, s.channel, s.username, slackPrefix(level)+formatNotification(message, fields))
+}
+
+// slackPrefix returns the emoji shortcode slack shows in front of a notification of the level
+func slackPrefix(level NotificationLevel) string {
+	switch level {
+	case LevelWarn:
+		return ":warning: "
+	case LevelError:
+		return ":sos: "
+	}
+	return ""
+}
+
+// DiscordNotifier posts notifications to a discord channel webhook
+type DiscordNotifier struct {
+	WebhookURL string
+	Username   string
+}
+
+func (d *DiscordNotifier) Info(message string, fields Fields) error {
+	return d.send(LevelInfo, message, fields)
+}
+
+func (d *DiscordNotifier) Warn(message string, fields Fields) error {
+	return d.send(LevelWarn, message, fields)
+}
+
+func (d *DiscordNotifier) Error(message string, fields Fields) error {
+	return d.send(LevelError, message, fields)
+}
+
+func (d *DiscordNotifier) send(level NotificationLevel, message string, fields Fields) error {
+	return postJSON(d.WebhookURL, map[string]string{
+		"content":  discordPrefix(level) + formatNotification(message, fields),
+		"username": d.Username,
+	})
+}
+
+// discordPrefix returns the bold marker discord shows in front of a notification of the level
+func discordPrefix(level NotificationLevel) string {
+	switch level {
+	case LevelWarn:
+		return "**WARNING** "
+	case LevelError:
+		return "**ERROR** "
+	}
+	return ""
+}
+
+// WebhookNotification is the body WebhookNotifier posts for each notification
+type WebhookNotification struct {
+	Level     NotificationLevel `json:"level"`
+	Message   string            `json:"message"`
+	Fields    Fields            `json:"fields,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// WebhookNotifier posts each notification as a JSON WebhookNotification to an arbitrary URL
+type WebhookNotifier struct {
+	URL string
+}
+
+func (w *WebhookNotifier) Info(message string, fields Fields) error {
+	return w.send(LevelInfo, message, fields)
+}
+
+func (w *WebhookNotifier) Warn(message string, fields Fields) error {
+	return w.send(LevelWarn, message, fields)
+}
+
+func (w *WebhookNotifier) Error(message string, fields Fields) error {
+	return w.send(LevelError, message, fields)
+}
+
+func (w *WebhookNotifier) send(level NotificationLevel, message string, fields Fields) error {
+	return postJSON(w.URL, WebhookNotification{
+		Level:     level,
+		Message:   message,
+		Fields:    fields,
+		Timestamp: time.Now(),
+	})
+}
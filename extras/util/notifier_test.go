@@ -0,0 +1,79 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFormatNotification(t *testing.T) {
+	got := formatNotification("sync failed", Fields{"videos": 3, "channel": "@test"})
+	expected := "sync failed channel=@test videos=3"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if got := slackPrefix(LevelError) + formatNotification("done", nil); got != ":sos: done" {
+		t.Errorf("expected %q, got %q", ":sos: done", got)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received WebhookNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&received)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	var n Notifier = &WebhookNotifier{URL: server.URL}
+	err := n.Warn("low balance", Fields{"balance": "0.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Level != LevelWarn || received.Message != "low balance" || received.Fields["balance"] != "0.5" {
+		t.Errorf("unexpected notification %+v", received)
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := (&DiscordNotifier{WebhookURL: server.URL}).Error("boom", nil)
+	if err == nil {
+		t.Error("expected an error for a failed webhook")
+	}
+}
+
+func TestDiscordNotifier(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&received)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	notifier := &DiscordNotifier{WebhookURL: server.URL, Username: "sync"}
+	err := notifier.Error("sync failed", Fields{"videos": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received["content"] != "**ERROR** sync failed videos=3" || received["username"] != "sync" {
+		t.Errorf("unexpected discord message %v", received)
+	}
+
+	err = notifier.Info("sync failed", Fields{"videos": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received["content"] != "sync failed videos=3" {
+		t.Errorf("expected info to have no marker, got %v", received)
+	}
+}
@@ -0,0 +1,116 @@
+// Package retry runs operations again after a failure, waiting longer between each attempt.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// Policy controls how many times an operation is attempted and how long to wait between attempts
+type Policy struct {
+	// MaxAttempts is the number of attempts before giving up. 0 means no limit; the context still applies.
+	MaxAttempts int
+	// MinInterval is the wait after the first failure
+	MinInterval time.Duration
+	// MaxInterval caps the wait between attempts. 0 means no cap.
+	MaxInterval time.Duration
+	// Multiplier grows the wait after each failure. Values below 1 are treated as 2.
+	Multiplier float64
+	// Jitter randomizes each wait by up to this fraction of it, so many clients retrying at once spread out. 0 to 1.
+	Jitter float64
+	// OnRetry, if set, is called after each failed attempt that will be retried, e.g. for logging or metrics
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// Default retries 5 times, starting at one second and doubling up to 30 seconds
+var Default = Policy{
+	MaxAttempts: 5,
+	MinInterval: 1 * time.Second,
+	MaxInterval: 30 * time.Second,
+	Multiplier:  2,
+	Jitter:      0.2,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (p permanentError) Error() string {
+	return p.err.Error()
+}
+
+// Permanent marks an error as not worth retrying. Do returns the wrapped error right away.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// canceledError is returned when the context ends the retries. It matches both the context error and the error from
+// the last attempt.
+type canceledError struct {
+	ctxErr error
+	last   error
+}
+
+func (c canceledError) Error() string {
+	return c.ctxErr.Error() + ": " + c.last.Error()
+}
+
+func (c canceledError) Unwrap() error {
+	return c.last
+}
+
+func (c canceledError) Is(target error) bool {
+	return target == c.ctxErr
+}
+
+// Do calls op until it succeeds, returns a Permanent error, runs out of attempts, or ctx is done. It returns the error
+// from the last attempt, prefixed with the context error if ctx ended the retries. errors.Is matches either of them
+// then.
+func Do(ctx context.Context, p Policy, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if permanent, ok := err.(permanentError); ok {
+			return permanent.err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return errors.Prefix(fmt.Sprintf("giving up after %d attempts", attempt), err)
+		}
+
+		wait := p.Backoff(attempt)
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Err(canceledError{ctxErr: ctx.Err(), last: err})
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Backoff returns how long to wait after the given failed attempt, counting from 1
+func (p Policy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	wait := float64(p.MinInterval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && wait > float64(p.MaxInterval) {
+		wait = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
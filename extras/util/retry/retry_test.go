@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+var errTemporary = errors.Base("temporary")
+
+func TestDo(t *testing.T) {
+	attempts := 0
+	var retries []int
+	p := Policy{MaxAttempts: 5, MinInterval: time.Millisecond, OnRetry: func(attempt int, err error, wait time.Duration) {
+		retries = append(retries, attempt)
+	}}
+
+	err := Do(context.Background(), p, func() error {
+		attempts++
+		if attempts < 3 {
+			return errTemporary
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || len(retries) != 2 {
+		t.Errorf("expected 3 attempts and 2 retries, got %d and %v", attempts, retries)
+	}
+}
+
+func TestDo_MaxAttempts(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), Policy{MaxAttempts: 3, MinInterval: time.Millisecond}, func() error {
+		attempts++
+		return errTemporary
+	})
+	if !errors.Is(err, errTemporary) || attempts != 3 {
+		t.Errorf("expected to give up after 3 attempts, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestDo_Permanent(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), Default, func() error {
+		attempts++
+		return Permanent(errTemporary)
+	})
+	if err != errTemporary || attempts != 1 {
+		t.Errorf("expected a permanent error to stop retries, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestDo_Context(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := Do(ctx, Policy{MinInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond}, func() error {
+		return errTemporary
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context to end the retries, got %v", err)
+	}
+	if !errors.Is(err, errTemporary) {
+		t.Errorf("expected the error from the last attempt to match, got %v", err)
+	}
+	if err.Error() != "context deadline exceeded: temporary" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{MinInterval: time.Second, MaxInterval: 5 * time.Second, Multiplier: 2}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		if got := p.Backoff(i + 1); got != e {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, e, got)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.Backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("jittered wait %s is out of range", got)
+		}
+	}
+}
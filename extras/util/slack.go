@@ -30,7 +30,7 @@ func SendToSlackUser(user, username, format string, a ...interface{}) error {
 	if !strings.HasPrefix(user, "@") {
 		user = "@" + user
 	}
-	return sendToSlack(user, username, message)
+	return sendToSlack(slackApi, user, username, message)
 }
 
 // SendToSlackChannel Sends message to a specific channel.
@@ -42,7 +42,7 @@ func SendToSlackChannel(channel, username, format string, a ...interface{}) erro
 	if !strings.HasPrefix(channel, "#") {
 		channel = "#" + channel
 	}
-	return sendToSlack(channel, username, message)
+	return sendToSlack(slackApi, channel, username, message)
 }
 
 // SendToSlack Sends message to the default channel.
@@ -55,17 +55,17 @@ func SendToSlack(format string, a ...interface{}) error {
 		return errors.Err("no default slack channel set")
 	}
 
-	return sendToSlack(defaultChannel, defaultUsername, message)
+	return sendToSlack(slackApi, defaultChannel, defaultUsername, message)
 }
 
-func sendToSlack(channel, username, message string) error {
+func sendToSlack(api *slack.Client, channel, username, message string) error {
 	var err error
 
-	if slackApi == nil {
+	if api == nil {
 		err = errors.Err("no slack token provided")
 	} else {
 		log.Debugln("slack: " + channel + ": " + message)
-		_, _, err = slackApi.PostMessage(channel, slack.MsgOptionText(message, false), slack.MsgOptionUsername(username))
+		_, _, err = api.PostMessage(channel, slack.MsgOptionText(message, false), slack.MsgOptionUsername(username))
 	}
 
 	if err != nil {
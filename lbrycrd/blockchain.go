@@ -1,12 +1,6 @@
 package lbrycrd
 
-import (
-	"crypto/sha256"
-	"encoding/binary"
-	"encoding/hex"
-
-	"golang.org/x/crypto/ripemd160"
-)
+import "github.com/lbryio/lbry.go/v2/schema/stake"
 
 // rev reverses a byte slice. useful for switching endian-ness
 func rev(b []byte) []byte {
@@ -17,31 +11,7 @@ func rev(b []byte) []byte {
 	return r
 }
 
+// ClaimIDFromOutpoint returns the ID of the claim created by the given transaction output
 func ClaimIDFromOutpoint(txid string, nout int) (string, error) {
-	// convert transaction id to byte array
-	txidBytes, err := hex.DecodeString(txid)
-	if err != nil {
-		return "", err
-	}
-
-	// reverse (make big-endian)
-	txidBytes = rev(txidBytes)
-
-	// append nout
-	noutBytes := make([]byte, 4) // num bytes in uint32
-	binary.BigEndian.PutUint32(noutBytes, uint32(nout))
-	txidBytes = append(txidBytes, noutBytes...)
-
-	// sha256 it
-	s := sha256.New()
-	s.Write(txidBytes)
-
-	// ripemd it
-	r := ripemd160.New()
-	r.Write(s.Sum(nil))
-
-	// reverse (make little-endian)
-	res := rev(r.Sum(nil))
-
-	return hex.EncodeToString(res), nil
+	return stake.ClaimIDFromOutpoint(txid, nout)
 }
@@ -0,0 +1,107 @@
+package lbrycrd
+
+import (
+	"encoding/json"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	c "github.com/lbryio/lbry.go/v2/schema/stake"
+)
+
+// The claimtrie calls below are lbrycrd extensions that rpcclient does not know about. Standard calls such as GetBlock,
+// SendToAddress and Generate (regtest only) are available on the embedded rpcclient.Client.
+
+// TrieSupport is a support for a claim, as reported by lbrycrd. Amounts are in dewies.
+type TrieSupport struct {
+	TxID          string `json:"txId"`
+	N             uint32 `json:"n"`
+	Height        int32  `json:"height"`
+	ValidAtHeight int32  `json:"validAtHeight"`
+	Amount        int64  `json:"amount"`
+	Address       string `json:"address,omitempty"`
+}
+
+// TrieClaim is a claim in the claimtrie, as reported by lbrycrd. Amounts are in dewies.
+type TrieClaim struct {
+	Name               string        `json:"name"`
+	NormalizedName     string        `json:"normalizedName"`
+	ClaimID            string        `json:"claimId"`
+	TxID               string        `json:"txId"`
+	N                  uint32        `json:"n"`
+	Height             int32         `json:"height"`
+	ValidAtHeight      int32         `json:"validAtHeight"`
+	Amount             int64         `json:"amount"`
+	EffectiveAmount    int64         `json:"effectiveAmount"`
+	LastTakeoverHeight int32         `json:"lastTakeoverHeight"`
+	Address            string        `json:"address,omitempty"`
+	Value              string        `json:"value"`
+	Supports           []TrieSupport `json:"supports"`
+}
+
+// Decode decodes the claim value
+func (t *TrieClaim) Decode(blockchainName string) (*c.StakeHelper, error) {
+	return c.DecodeClaimHex(t.Value, blockchainName)
+}
+
+// ClaimsForName is every claim competing for a name
+type ClaimsForName struct {
+	NormalizedName       string        `json:"normalizedName"`
+	LastTakeoverHeight   int32         `json:"lastTakeoverHeight"`
+	Claims               []TrieClaim   `json:"claims"`
+	SupportsWithoutClaim []TrieSupport `json:"supportsWithoutClaim"`
+}
+
+// GetClaimsForName returns every claim for the name, including ones that are not yet active
+func (c *Client) GetClaimsForName(name string) (*ClaimsForName, error) {
+	response := new(ClaimsForName)
+	return response, c.claimtrieCall(response, "getclaimsforname", name)
+}
+
+// GetValueForName returns the controlling claim for the name, or nil if nobody controls it
+func (c *Client) GetValueForName(name string) (*TrieClaim, error) {
+	response := new(TrieClaim)
+	err := c.claimtrieCall(response, "getvalueforname", name)
+	if err != nil {
+		return nil, err
+	}
+	if response.ClaimID == "" {
+		return nil, nil
+	}
+	return response, nil
+}
+
+// GetClaimByID returns the claim with the given ID, or nil if there is no such claim in the trie
+func (c *Client) GetClaimByID(claimID string) (*TrieClaim, error) {
+	response := new(TrieClaim)
+	err := c.claimtrieCall(response, "getclaimbyid", claimID)
+	if err != nil {
+		return nil, err
+	}
+	if response.ClaimID == "" {
+		return nil, nil
+	}
+	return response, nil
+}
+
+func (c *Client) claimtrieCall(response interface{}, method string, params ...interface{}) error {
+	rawParams := make([]json.RawMessage, len(params))
+	for i, param := range params {
+		raw, err := json.Marshal(param)
+		if err != nil {
+			return errors.Err(err)
+		}
+		rawParams[i] = raw
+	}
+
+	result, err := c.RawRequest(method, rawParams)
+	if err != nil {
+		return errors.Prefix(method, errors.Err(err))
+	}
+	if len(result) == 0 || string(result) == "null" {
+		return nil
+	}
+	err = json.Unmarshal(result, response)
+	if err != nil {
+		return errors.Prefix(method, errors.Err(err))
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package lbrycrd
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 
 	"github.com/lbryio/lbry.go/v2/extras/errors"
@@ -74,3 +75,118 @@ func getUpdateClaimPayoutScript(name, claimid string, value []byte, address btcu
 		AddOps(pkscript).         //OP_DUP OP_HASH160 <address> OP_EQUALVERIFY OP_CHECKSIG
 		Script()
 }
+
+// ClaimOp is the claimtrie operation an output script performs
+type ClaimOp int
+
+const (
+	ClaimOpNone ClaimOp = iota
+	ClaimOpCreate
+	ClaimOpUpdate
+	ClaimOpSupport
+)
+
+// String returns the name the daemon uses for the operation in its claim_op field
+func (o ClaimOp) String() string {
+	switch o {
+	case ClaimOpCreate:
+		return "create"
+	case ClaimOpUpdate:
+		return "update"
+	case ClaimOpSupport:
+		return "support"
+	}
+	return ""
+}
+
+// ClaimScript is the claimtrie part of an output script. ClaimID is empty for ClaimOpCreate, since a new claim's ID
+// depends on the outpoint and not on the script. Use ClaimIDFromOutpoint for those.
+type ClaimScript struct {
+	Op           ClaimOp
+	Name         string
+	ClaimID      string
+	Value        []byte
+	PayoutScript []byte
+}
+
+// ParseClaimScript parses the claim, update or support prefix of an output script. Scripts without a claim prefix are
+// returned with Op set to ClaimOpNone and the whole script as the payout script.
+func ParseClaimScript(script []byte) (*ClaimScript, error) {
+	cs := &ClaimScript{PayoutScript: script}
+	if len(script) == 0 {
+		return cs, nil
+	}
+
+	var pushes int
+	switch script[0] {
+	case txscript.OP_NOP6: //OP_CLAIM_NAME <name> <value>
+		cs.Op, pushes = ClaimOpCreate, 2
+	case txscript.OP_NOP7: //OP_SUPPORT_CLAIM <name> <claimid>
+		cs.Op, pushes = ClaimOpSupport, 2
+	case txscript.OP_NOP8: //OP_UPDATE_CLAIM <name> <claimid> <value>
+		cs.Op, pushes = ClaimOpUpdate, 3
+	default:
+		return cs, nil
+	}
+
+	rest := script[1:]
+	data := make([][]byte, pushes)
+	for i := range data {
+		var err error
+		data[i], rest, err = readPush(rest)
+		if err != nil {
+			return nil, errors.Prefix(cs.Op.String()+" script", err)
+		}
+	}
+	for len(rest) > 0 && (rest[0] == txscript.OP_2DROP || rest[0] == txscript.OP_DROP) {
+		rest = rest[1:]
+	}
+
+	cs.Name = string(data[0])
+	switch cs.Op {
+	case ClaimOpCreate:
+		cs.Value = data[1]
+	case ClaimOpSupport:
+		cs.ClaimID = hex.EncodeToString(rev(data[1]))
+	case ClaimOpUpdate:
+		cs.ClaimID = hex.EncodeToString(rev(data[1]))
+		cs.Value = data[2]
+	}
+	cs.PayoutScript = rest
+	return cs, nil
+}
+
+// readPush reads a single data push from the start of script and returns the pushed data and the remaining script
+func readPush(script []byte) ([]byte, []byte, error) {
+	if len(script) == 0 {
+		return nil, nil, errors.Err("unexpected end of script")
+	}
+	op := script[0]
+	script = script[1:]
+
+	var size int
+	switch {
+	case op == txscript.OP_0:
+		return []byte{}, script, nil
+	case op == txscript.OP_1NEGATE:
+		// minimal encoding pushes these single byte values with their own opcodes
+		return []byte{0x81}, script, nil
+	case op >= txscript.OP_1 && op <= txscript.OP_16:
+		return []byte{op - txscript.OP_1 + 1}, script, nil
+	case op <= txscript.OP_DATA_75:
+		size = int(op)
+	case op == txscript.OP_PUSHDATA1 && len(script) >= 1:
+		size, script = int(script[0]), script[1:]
+	case op == txscript.OP_PUSHDATA2 && len(script) >= 2:
+		size, script = int(binary.LittleEndian.Uint16(script)), script[2:]
+	case op == txscript.OP_PUSHDATA4 && len(script) >= 4:
+		size, script = int(binary.LittleEndian.Uint32(script)), script[4:]
+	default:
+		return nil, nil, errors.Err("expected a data push, got opcode 0x%x", op)
+	}
+
+	if size > len(script) {
+		return nil, nil, errors.Err("data push of %d bytes exceeds script length", size)
+	}
+	return script[:size], script[size:], nil
+}
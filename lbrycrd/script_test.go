@@ -0,0 +1,90 @@
+package lbrycrd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestParseClaimScript(t *testing.T) {
+	address, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payout, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimID := "589bc4845caca70977332025990b2a1807732b44"
+	value := bytes.Repeat([]byte{0x42}, 300)
+
+	create, err := getClaimNamePayoutScript("test", value, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update, err := getUpdateClaimPayoutScript("test", claimID, value, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	support, err := getClaimSupportPayoutScript("test", claimID, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		script  []byte
+		op      ClaimOp
+		claimID string
+		value   []byte
+	}{
+		{create, ClaimOpCreate, "", value},
+		{update, ClaimOpUpdate, claimID, value},
+		{support, ClaimOpSupport, claimID, nil},
+	}
+	for _, test := range tests {
+		cs, err := ParseClaimScript(test.script)
+		if err != nil {
+			t.Errorf("%s: %v", test.op, err)
+			continue
+		}
+		if cs.Op != test.op || cs.Name != "test" || cs.ClaimID != test.claimID || !bytes.Equal(cs.Value, test.value) {
+			t.Errorf("%s: unexpected parse result %+v", test.op, cs)
+		}
+		if !bytes.Equal(cs.PayoutScript, payout) {
+			t.Errorf("%s: expected payout script %x, got %x", test.op, payout, cs.PayoutScript)
+		}
+	}
+
+	cs, err := ParseClaimScript(payout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs.Op != ClaimOpNone || !bytes.Equal(cs.PayoutScript, payout) {
+		t.Errorf("expected a plain payout script to pass through, got %+v", cs)
+	}
+
+	// the script builder pushes single byte values 1 to 16 and 0x81 with small integer opcodes
+	for _, data := range [][]byte{{1}, {16}, {0x81}} {
+		script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_NOP6).AddData([]byte("test")).AddData(data).
+			AddOp(txscript.OP_2DROP).AddOp(txscript.OP_DROP).AddOps(payout).Script()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs, err := ParseClaimScript(script)
+		if err != nil {
+			t.Errorf("value %x: %v", data, err)
+			continue
+		}
+		if !bytes.Equal(cs.Value, data) || !bytes.Equal(cs.PayoutScript, payout) {
+			t.Errorf("value %x: unexpected parse result %+v", data, cs)
+		}
+	}
+
+	_, err = ParseClaimScript(create[:10])
+	if err == nil {
+		t.Error("expected an error for a truncated claim script")
+	}
+}
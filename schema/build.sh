@@ -3,3 +3,4 @@
 set -euxo pipefail
 go build ./...
 go build ./cli/lbryschema-cli.go
+GOOS=js GOARCH=wasm go build -o /dev/null ./wasm
@@ -1,6 +1,7 @@
 package keys
 
 import (
+	"bytes"
 	"crypto/elliptic"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -11,6 +12,11 @@ import (
 	"github.com/btcsuite/btcd/btcec"
 )
 
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
 type publicKeyInfo struct {
 	Raw       asn1.RawContent
 	Algorithm pkix.AlgorithmIdentifier
@@ -24,9 +30,9 @@ func PublicKeyToDER(publicKey *btcec.PublicKey) ([]byte, error) {
 	pub := publicKey.ToECDSA()
 	publicKeyBytes = elliptic.Marshal(pub.Curve, pub.X, pub.Y)
 	//ans1 encoding oid for ecdsa public key https://github.com/golang/go/blob/release-branch.go1.12/src/crypto/x509/x509.go#L457
-	publicKeyAlgorithm.Algorithm = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	publicKeyAlgorithm.Algorithm = oidPublicKeyECDSA
 	//asn1 encoding oid for secp256k1 https://github.com/bitpay/bitpay-go/blob/v2.2.2/key_utils/key_utils.go#L30
-	paramBytes, err := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	paramBytes, err := asn1.Marshal(oidSecp256k1)
 	if err != nil {
 		return nil, errors.Err(err)
 	}
@@ -52,7 +58,7 @@ type ecPrivateKey struct {
 
 func PrivateKeyToDER(key *btcec.PrivateKey) ([]byte, error) {
 	privateKey := make([]byte, (key.Curve.Params().N.BitLen()+7)/8)
-	oid := asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oid := oidSecp256k1
 	return asn1.Marshal(ecPrivateKey{
 		Version:       1,
 		PrivateKey:    key.D.FillBytes(privateKey),
@@ -61,13 +67,42 @@ func PrivateKeyToDER(key *btcec.PrivateKey) ([]byte, error) {
 	})
 }
 
+// GetPublicKeyFromBytes parses a DER encoded secp256k1 public key. Only the canonical DER encoding is accepted, so a
+// key can't be given more than one encoding.
 func GetPublicKeyFromBytes(pubKeyBytes []byte) (*btcec.PublicKey, error) {
 	PKInfo := publicKeyInfo{}
-	_, err := asn1.Unmarshal(pubKeyBytes, &PKInfo)
+	rest, err := asn1.Unmarshal(pubKeyBytes, &PKInfo)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.Err("public key has %d bytes of trailing data", len(rest))
+	}
+	if !PKInfo.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, errors.Err("public key is not an ecdsa key")
+	}
+	var curve asn1.ObjectIdentifier
+	rest, err = asn1.Unmarshal(PKInfo.Algorithm.Parameters.FullBytes, &curve)
+	if err != nil || len(rest) > 0 || !curve.Equal(oidSecp256k1) {
+		return nil, errors.Err("public key is not on the secp256k1 curve")
+	}
+	if PKInfo.PublicKey.BitLength != 8*len(PKInfo.PublicKey.Bytes) {
+		return nil, errors.Err("public key is not a whole number of bytes")
+	}
+	// go's asn1 ignores extra elements in a sequence, so re-encoding is the simplest way to be sure the input was DER
+	PKInfo.Raw = nil
+	canonical, err := asn1.Marshal(PKInfo)
 	if err != nil {
 		return nil, errors.Err(err)
 	}
+	if !bytes.Equal(canonical, pubKeyBytes) {
+		return nil, errors.Err("public key is not DER encoded")
+	}
 	pubkeyBytes1 := []byte(PKInfo.PublicKey.Bytes)
+	// btcec also parses the hybrid format, which is yet another encoding of the same key
+	if len(pubkeyBytes1) == 0 || (pubkeyBytes1[0] != 0x02 && pubkeyBytes1[0] != 0x03 && pubkeyBytes1[0] != 0x04) {
+		return nil, errors.Err("public key point is not in compressed or uncompressed format")
+	}
 	return btcec.ParsePubKey(pubkeyBytes1, btcec.S256())
 }
 
@@ -98,8 +133,9 @@ func (s *Signature) LBRYSDKEncode() ([]byte, error) {
 	if s.R == nil || s.S == nil {
 		return nil, errors.Err("invalid signature, both S & R are nil")
 	}
-	rBytes := s.R.Bytes()
-	sBytes := s.S.Bytes()
-
-	return append(rBytes, sBytes...), nil
+	// R and S are padded to 32 bytes each, since the SDK splits the signature in the middle
+	encoded := make([]byte, 64)
+	s.R.FillBytes(encoded[:32])
+	s.S.FillBytes(encoded[32:])
+	return encoded, nil
 }
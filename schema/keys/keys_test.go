@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/pem"
+	"math/big"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -93,3 +94,43 @@ func TestEncodePEMAndBack(t *testing.T) {
 		t.Error("private keys dont match")
 	}
 }
+
+func TestSignature_LBRYSDKEncodePadding(t *testing.T) {
+	sig := Signature{btcec.Signature{R: big.NewInt(1), S: big.NewInt(2)}}
+	encoded, err := sig.LBRYSDKEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encoded) != 64 || encoded[31] != 1 || encoded[63] != 2 {
+		t.Errorf("expected R and S padded to 32 bytes, got %x", encoded)
+	}
+}
+
+func TestGetPublicKeyFromBytes_StrictDER(t *testing.T) {
+	publicKeyHex := "3056301006072a8648ce3d020106052b8104000a03420004d015365a40f3e5c03c87227168e5851f44659837bcf6a3398ae633bc37d04ee19baeb26dc888003bd728146dbea39f5344bf8c52cedaf1a3a1623a0166f4a367"
+	point := publicKeyHex[len("3056301006072a8648ce3d020106052b8104000a0342000"):]
+	malformed := map[string]string{
+		"trailing data":       publicKeyHex + "00",
+		"non-minimal length":  "308156" + publicKeyHex[4:],
+		"extra element":       "3058" + publicKeyHex[4:] + "0500",
+		"other curve":         "3056301006072a8648ce3d020106052b810400220342000" + point,
+		"hybrid point, even":  "3056301006072a8648ce3d020106052b8104000a0342000" + "6" + point[1:],
+		"hybrid point, odd":   "3056301006072a8648ce3d020106052b8104000a0342000" + "7" + point[1:],
+		"unused bits set":     "3056301006072a8648ce3d020106052b8104000a03420104" + point[1:],
+		"not a key at all":    "00",
+		"empty":               "",
+		"truncated":           publicKeyHex[:len(publicKeyHex)-2],
+		"other algorithm":     "3056301006072a8648ce3d020206052b8104000a03420004" + point[1:],
+		"non-canonical point": "3056301006072a8648ce3d020106052b8104000a03420005" + point[1:],
+	}
+	for name, keyHex := range malformed {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		_, err = GetPublicKeyFromBytes(key)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
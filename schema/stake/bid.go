@@ -0,0 +1,58 @@
+package stake
+
+import "context"
+
+// NameClaim is a claim competing for a name. Amounts are in dewies.
+type NameClaim struct {
+	ClaimID         string
+	Amount          uint64
+	EffectiveAmount uint64
+	IsControlling   bool
+}
+
+// NameResolver looks up all the claims currently competing for a name. It is implemented by jsonrpc.Client.
+type NameResolver interface {
+	ClaimsForName(ctx context.Context, name string) ([]NameClaim, error)
+}
+
+// BidAdvice describes what it takes to win a name. Amounts are in dewies.
+type BidAdvice struct {
+	Name               string
+	Available          bool
+	WinningClaimID     string
+	WinningAmount      uint64
+	MinimumTakeoverBid uint64
+}
+
+// AdviseBid finds the claim currently winning the name and the smallest bid that would take it over. A name with no
+// claims is available and any non-zero bid wins it. Supports on a new claim also count towards a takeover, so the
+// minimum bid is an upper bound when supports are planned.
+func AdviseBid(ctx context.Context, resolver NameResolver, name string) (*BidAdvice, error) {
+	if err := ValidateClaimName(name); err != nil {
+		return nil, err
+	}
+	claims, err := resolver.ClaimsForName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	advice := &BidAdvice{Name: name, Available: len(claims) == 0, MinimumTakeoverBid: 1}
+	var winner *NameClaim
+	var highest uint64
+	for i := range claims {
+		c := &claims[i]
+		if c.EffectiveAmount > highest {
+			highest = c.EffectiveAmount
+		}
+		if winner == nil || c.IsControlling || (!winner.IsControlling && c.EffectiveAmount > winner.EffectiveAmount) {
+			winner = c
+		}
+	}
+	if winner != nil {
+		advice.WinningClaimID = winner.ClaimID
+		advice.WinningAmount = winner.EffectiveAmount
+		// a pending takeover may already outbid the controlling claim, so the new bid has to beat every claim
+		advice.MinimumTakeoverBid = highest + 1
+	}
+	return advice, nil
+}
@@ -0,0 +1,39 @@
+package stake
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+type staticResolver []NameClaim
+
+func (r staticResolver) ClaimsForName(ctx context.Context, name string) ([]NameClaim, error) {
+	return r, nil
+}
+
+func TestAdviseBid(t *testing.T) {
+	advice, err := AdviseBid(context.Background(), staticResolver(nil), "free")
+	assert.NilError(t, err)
+	assert.Assert(t, advice.Available)
+	assert.Equal(t, advice.MinimumTakeoverBid, uint64(1))
+
+	advice, err = AdviseBid(context.Background(), staticResolver{
+		{ClaimID: "a", Amount: 100, EffectiveAmount: 500, IsControlling: true},
+		{ClaimID: "b", Amount: 200, EffectiveAmount: 200},
+	}, "taken")
+	assert.NilError(t, err)
+	assert.Assert(t, !advice.Available)
+	assert.Equal(t, advice.WinningClaimID, "a")
+	assert.Equal(t, advice.WinningAmount, uint64(500))
+	assert.Equal(t, advice.MinimumTakeoverBid, uint64(501))
+
+	advice, err = AdviseBid(context.Background(), staticResolver{
+		{ClaimID: "a", EffectiveAmount: 500, IsControlling: true},
+		{ClaimID: "b", EffectiveAmount: 900},
+	}, "pending")
+	assert.NilError(t, err)
+	assert.Equal(t, advice.WinningClaimID, "a")
+	assert.Equal(t, advice.MinimumTakeoverBid, uint64(901))
+}
@@ -0,0 +1,34 @@
+package stake
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// ClaimIDFromOutpoint returns the ID of the claim created by the given transaction output
+func ClaimIDFromOutpoint(txid string, nout int) (string, error) {
+	// convert transaction id to byte array
+	txidBytes, err := hex.DecodeString(txid)
+	if err != nil {
+		return "", errors.Err(err)
+	}
+
+	// reverse (make big-endian)
+	txidBytes = reverseBytes(txidBytes)
+
+	// append nout
+	noutBytes := make([]byte, 4) // num bytes in uint32
+	binary.BigEndian.PutUint32(noutBytes, uint32(nout))
+	txidBytes = append(txidBytes, noutBytes...)
+
+	// hash160 it
+	digest, err := Digest(ClaimIDDigest)
+	if err != nil {
+		return "", err
+	}
+
+	// reverse (make little-endian)
+	return hex.EncodeToString(reverseBytes(digest(txidBytes))), nil
+}
@@ -0,0 +1,24 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package claimstore
+
+import (
+	"os"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// mmap falls back to reading the whole file on platforms without mmap support
+func mmap(f *os.File, size int64) ([]byte, error) {
+	data := make([]byte, size)
+	_, err := f.ReadAt(data, 0)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	return nil
+}
@@ -0,0 +1,29 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package claimstore
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+func mmap(f *os.File, size int64) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	if data == nil {
+		return nil
+	}
+	return errors.Err(syscall.Munmap(data))
+}
@@ -0,0 +1,408 @@
+/*
+Package claimstore is an append-only on-disk store of raw claim values, indexed by claim ID. It is meant for analytics
+jobs that need to scan millions of claims without running a database server.
+
+The store is a single file of records, each holding a claim ID, the value length and the serialized claim value. Writing
+a claim that is already in the store appends a new record, and the newest record wins. Reads go through a read-only
+memory map of the file. Values passed to the ForEach callback point straight into the map, so they must not be modified
+and are only valid until the callback returns. Get returns a copy.
+*/
+package claimstore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/schema/stake"
+)
+
+const (
+	claimIDLength = 20
+	headerLength  = claimIDLength + 4
+	// MaxValueSize is the largest claim value the store accepts
+	MaxValueSize = 1 << 24
+
+	// values written since the file was last mapped are kept in memory. Once they add up to as many bytes as are
+	// mapped, within these bounds, the file is mapped again, so a growing store is not remapped on every read.
+	minRemapBytes = 1 << 20
+	maxRemapBytes = 64 << 20
+)
+
+type claimHash [claimIDLength]byte
+
+// Store is an append-only claim store backed by a single file. It is safe for concurrent use.
+type Store struct {
+	mu     sync.RWMutex
+	file   *os.File
+	writer *bufio.Writer
+	size   int64 // bytes written, including those still buffered in writer
+	data   *mapping
+	// pending holds the latest value of every claim written since the file was last mapped
+	pending      map[claimHash][]byte
+	pendingBytes int
+	index        map[claimHash]int64
+	order        []claimHash
+}
+
+// mapping is a memory map of the file. A mapping that was replaced by a larger one is unmapped once no ForEach holds it.
+type mapping struct {
+	mu      sync.Mutex
+	bytes   []byte
+	readers int
+	retired bool
+}
+
+func (m *mapping) acquire() {
+	m.mu.Lock()
+	m.readers++
+	m.mu.Unlock()
+}
+
+func (m *mapping) release() error {
+	m.mu.Lock()
+	m.readers--
+	unmap := m.retired && m.readers == 0
+	m.mu.Unlock()
+	if unmap {
+		return munmap(m.bytes)
+	}
+	return nil
+}
+
+// retire unmaps the mapping now if nobody holds it, or else when the last reader releases it
+func (m *mapping) retire() error {
+	m.mu.Lock()
+	m.retired = true
+	unmap := m.readers == 0
+	m.mu.Unlock()
+	if unmap {
+		return munmap(m.bytes)
+	}
+	return nil
+}
+
+// Open opens the store at path, creating it if it does not exist. A partially written record at the end of the file,
+// as left by a crash during a write, is discarded.
+func Open(path string) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, errors.Err(err)
+	}
+
+	s := &Store{file: f, index: make(map[claimHash]int64), pending: make(map[claimHash][]byte)}
+	data, err := mmap(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.data = &mapping{bytes: data}
+
+	s.size = s.load()
+	if s.size < info.Size() {
+		err = f.Truncate(s.size)
+		if err == nil {
+			err = munmap(s.data.bytes)
+		}
+		if err == nil {
+			s.data.bytes, err = mmap(f, s.size)
+		}
+		if err != nil {
+			s.data = nil
+			f.Close()
+			return nil, errors.Err(err)
+		}
+	}
+	_, err = f.Seek(s.size, io.SeekStart)
+	if err != nil {
+		s.Close()
+		return nil, errors.Err(err)
+	}
+	s.writer = bufio.NewWriter(f)
+	return s, nil
+}
+
+// load rebuilds the index from the mapped file and returns the offset after the last complete record
+func (s *Store) load() int64 {
+	data := s.data.bytes
+	var offset int64
+	for offset+headerLength <= int64(len(data)) {
+		var hash claimHash
+		copy(hash[:], data[offset:])
+		length := int64(binary.BigEndian.Uint32(data[offset+claimIDLength:]))
+		end := offset + headerLength + length
+		if length > MaxValueSize || end > int64(len(data)) {
+			break
+		}
+		s.addToIndex(hash, offset)
+		offset = end
+	}
+	return offset
+}
+
+func (s *Store) addToIndex(hash claimHash, offset int64) {
+	if _, ok := s.index[hash]; !ok {
+		s.order = append(s.order, hash)
+	}
+	s.index[hash] = offset
+}
+
+// Close flushes pending writes and releases the file and its memory maps
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.writer != nil {
+		err = s.writer.Flush()
+	}
+	if s.data != nil {
+		if unmapErr := s.data.retire(); err == nil {
+			err = unmapErr
+		}
+	}
+	s.data, s.pending = nil, nil
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return errors.Err(err)
+}
+
+// Len returns the number of distinct claims in the store
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.index)
+}
+
+// Put appends a claim value to the store. Writes are buffered; they are visible to Get immediately and reach the disk
+// on Flush or Close. The store keeps a copy of value, so the caller may reuse it.
+func (s *Store) Put(claimID string, value []byte) error {
+	hash, err := parseClaimID(claimID)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.put(hash, value)
+}
+
+func (s *Store) put(hash claimHash, value []byte) error {
+	if s.data == nil {
+		return errors.Err("claim store is closed")
+	}
+	if len(value) > MaxValueSize {
+		return errors.Err("claim %s value is %d bytes, more than the maximum of %d", hex.EncodeToString(hash[:]), len(value), MaxValueSize)
+	}
+	var header [headerLength]byte
+	copy(header[:], hash[:])
+	binary.BigEndian.PutUint32(header[claimIDLength:], uint32(len(value)))
+
+	_, err := s.writer.Write(header[:])
+	if err != nil {
+		return errors.Err(err)
+	}
+	_, err = s.writer.Write(value)
+	if err != nil {
+		return errors.Err(err)
+	}
+	s.addToIndex(hash, s.size)
+	s.size += headerLength + int64(len(value))
+
+	if old, ok := s.pending[hash]; ok {
+		s.pendingBytes -= len(old)
+	}
+	s.pending[hash] = clone(value)
+	s.pendingBytes += len(value)
+	if s.pendingBytes >= remapThreshold(len(s.data.bytes)) {
+		return s.remap()
+	}
+	return nil
+}
+
+func remapThreshold(mapped int) int {
+	if mapped < minRemapBytes {
+		return minRemapBytes
+	}
+	if mapped > maxRemapBytes {
+		return maxRemapBytes
+	}
+	return mapped
+}
+
+// Flush writes buffered records to the file
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Err(s.writer.Flush())
+}
+
+// Get returns a copy of the latest value stored for the claim, or nil if the claim is not in the store
+func (s *Store) Get(claimID string) ([]byte, error) {
+	hash, err := parseClaimID(claimID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data == nil {
+		return nil, errors.Err("claim store is closed")
+	}
+	value, ok := s.value(hash)
+	if !ok {
+		return nil, nil
+	}
+	return clone(value), nil
+}
+
+// Decode returns the latest value stored for the claim, decoded. It returns nil if the claim is not in the store.
+func (s *Store) Decode(claimID string, blockchainName string) (*stake.StakeHelper, error) {
+	value, err := s.Get(claimID)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return stake.DecodeClaimBytes(value, blockchainName)
+}
+
+// ForEach calls fn with the latest value of every claim in the store, in the order the claims were first added. It
+// stops at the first error fn returns. The value is only valid until fn returns, and must not be modified.
+func (s *Store) ForEach(fn func(claimID string, value []byte) error) error {
+	s.mu.RLock()
+	order := s.order
+	s.mu.RUnlock()
+
+	// hold on to the mapping the values come from, so it is not unmapped while fn reads from it
+	var held *mapping
+	defer func() {
+		if held != nil {
+			_ = held.release()
+		}
+	}()
+
+	for _, hash := range order {
+		s.mu.RLock()
+		if s.data == nil {
+			s.mu.RUnlock()
+			return errors.Err("claim store is closed")
+		}
+		if held != s.data {
+			if held != nil {
+				_ = held.release()
+			}
+			held = s.data
+			held.acquire()
+		}
+		value, _ := s.value(hash)
+		s.mu.RUnlock()
+
+		err := fn(hex.EncodeToString(hash[:]), value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportHex reads lines of "<claim id> <value hex>" from r and appends them to the store. The two fields can be separated
+// by whitespace or a comma, and blank lines are skipped. It returns the number of claims imported.
+func (s *Store) ImportHex(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 2*MaxValueSize+2*claimIDLength+2)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imported := 0
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.FieldsFunc(scanner.Text(), func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return imported, errors.Err("line %d: expected a claim id and a value, got %d fields", line, len(fields))
+		}
+		hash, err := parseClaimID(fields[0])
+		if err != nil {
+			return imported, errors.Prefix("line "+strconv.Itoa(line), err)
+		}
+		value, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return imported, errors.Prefix("line "+strconv.Itoa(line), errors.Err(err))
+		}
+		err = s.put(hash, value)
+		if err != nil {
+			return imported, err
+		}
+		imported++
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, errors.Err(err)
+	}
+	return imported, errors.Err(s.writer.Flush())
+}
+
+// remap flushes buffered writes and maps the whole file, so values no longer need to be kept in memory. The old
+// mapping is unmapped once no ForEach holds it.
+func (s *Store) remap() error {
+	err := s.writer.Flush()
+	if err != nil {
+		return errors.Err(err)
+	}
+	data, err := mmap(s.file, s.size)
+	if err != nil {
+		return err
+	}
+	old := s.data
+	s.data = &mapping{bytes: data}
+	s.pending = make(map[claimHash][]byte)
+	s.pendingBytes = 0
+	return old.retire()
+}
+
+// value returns the latest value of the claim, from the map or from the values not mapped yet. s.mu must be held.
+func (s *Store) value(hash claimHash) ([]byte, bool) {
+	offset, ok := s.index[hash]
+	if !ok {
+		return nil, false
+	}
+	data := s.data.bytes
+	if offset >= int64(len(data)) {
+		return s.pending[hash], true
+	}
+	length := int64(binary.BigEndian.Uint32(data[offset+claimIDLength:]))
+	start := offset + headerLength
+	return data[start : start+length : start+length], true
+}
+
+// clone copies value. Unlike append to nil, it keeps empty values non-nil, since nil means the claim is not stored.
+func clone(value []byte) []byte {
+	c := make([]byte, len(value))
+	copy(c, value)
+	return c
+}
+
+func parseClaimID(claimID string) (claimHash, error) {
+	var hash claimHash
+	b, err := hex.DecodeString(claimID)
+	if err != nil {
+		return hash, errors.Err("invalid claim id %q: %v", claimID, err)
+	}
+	if len(b) != claimIDLength {
+		return hash, errors.Err("invalid claim id %q: expected %d bytes, got %d", claimID, claimIDLength, len(b))
+	}
+	copy(hash[:], b)
+	return hash, nil
+}
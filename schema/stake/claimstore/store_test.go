@@ -0,0 +1,241 @@
+package claimstore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	claimA = "589bc4845caca70977332025990b2a1807732b44"
+	claimB = "60d7ddcc211c381bad63b73415c2065b219258f2"
+	// a stream claim, from the stake package tests
+	streamHex = "080110011ad7010801128f01080410011a0c47616d65206f66206c696665221047616d65206f66206c696665206769662a0b4a6f686e20436f6e776179322e437265617469766520436f6d6d6f6e73204174747269627574696f6e20342e3020496e7465726e6174696f6e616c38004224080110011a195569c917f18bf5d2d67f1346aa467b218ba90cdbf2795676da250000803f4a0052005a001a41080110011a30b6adf6e2a62950407ea9fb045a96127b67d39088678d2f738c359894c88d95698075ee6203533d3c204330713aa7acaf2209696d6167652f6769662a5c080110031a40c73fe1be4f1743c2996102eec6ce0509e03744ab940c97d19ddb3b25596206367ab1a3d2583b16c04d2717eeb983ae8f84fee2a46621ffa5c4726b30174c6ff82214251305ca93d4dbedb50dceb282ebcb7b07b7ac65"
+)
+
+func openTemp(t *testing.T) (*Store, string) {
+	path := filepath.Join(t.TempDir(), "claims.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func TestStore_PutGet(t *testing.T) {
+	s, path := openTemp(t)
+
+	if err := s.Put(claimA, []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(claimB, []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(claimA, []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := s.Get(claimA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "second" {
+		t.Errorf("expected the latest value, got %q", value)
+	}
+	if s.Len() != 2 {
+		t.Errorf("expected 2 claims, got %d", s.Len())
+	}
+	missing, err := s.Get("0000000000000000000000000000000000000000")
+	if err != nil || missing != nil {
+		t.Errorf("expected no value for a missing claim, got %q, %v", missing, err)
+	}
+	if _, err := s.Get("abcd"); err == nil {
+		t.Error("expected an error for a short claim id")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	value, err = s.Get(claimA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "second" || s.Len() != 2 {
+		t.Errorf("store was not reloaded correctly: %q, %d claims", value, s.Len())
+	}
+}
+
+func TestStore_ImportHexAndForEach(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+
+	dump := claimA + " " + streamHex + "\n\n" + claimB + "," + streamHex + "\n"
+	n, err := s.ImportHex(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 claims imported, got %d", n)
+	}
+
+	var ids []string
+	err = s.ForEach(func(claimID string, value []byte) error {
+		ids = append(ids, claimID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != claimA || ids[1] != claimB {
+		t.Errorf("unexpected iteration order %v", ids)
+	}
+
+	claim, err := s.Decode(claimB, "lbrycrd_main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.GetStream() == nil {
+		t.Error("expected a stream claim")
+	}
+
+	_, err = s.ImportHex(strings.NewReader(claimA + " zz\n"))
+	if err == nil {
+		t.Error("expected an error for an invalid hex value")
+	}
+}
+
+func TestStore_TruncatedRecord(t *testing.T) {
+	s, path := openTemp(t)
+	if err := s.Put(claimA, []byte("complete")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(claimB, bytes.Repeat([]byte{1}, 100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()-10); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Len() != 1 {
+		t.Errorf("expected the truncated record to be dropped, got %d claims", s.Len())
+	}
+	if err := s.Put(claimB, []byte("rewritten")); err != nil {
+		t.Fatal(err)
+	}
+	value, err := s.Get(claimB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "rewritten" {
+		t.Errorf("expected the rewritten value, got %q", value)
+	}
+}
+
+func TestStore_InterleavedPutGet(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+
+	first := s.data
+	for i := 0; i < 1000; i++ {
+		value := bytes.Repeat([]byte{byte(i)}, 100)
+		if err := s.Put(claimA, value); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Get(claimA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, value) {
+			t.Fatalf("put %d: expected the value just written", i)
+		}
+	}
+	if s.data != first {
+		t.Error("expected values written since the last map to be read from memory, not by mapping the file again")
+	}
+
+	if err := s.Put(claimB, []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := s.Get(claimB); err != nil || value == nil || len(value) != 0 {
+		t.Errorf("expected an empty value, got %v, %v", value, err)
+	}
+}
+
+func TestStore_RemapReleasesOldMaps(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+
+	big := bytes.Repeat([]byte{1}, minRemapBytes/4)
+	if err := s.Put(claimA, big); err != nil {
+		t.Fatal(err)
+	}
+	held := s.data
+	held.acquire()
+	for i := 0; i < 4; i++ {
+		if err := s.Put(fmt.Sprintf("%040x", i), big); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.data == held {
+		t.Fatal("expected the file to be mapped again once enough values were written")
+	}
+	if !held.retired {
+		t.Error("expected the replaced map to be retired")
+	}
+	// a ForEach reading from the old map keeps it mapped until it is done
+	if held.readers != 1 {
+		t.Errorf("expected the old map to still be held, got %d readers", held.readers)
+	}
+	if err := held.release(); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := s.Get(claimA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, big) {
+		t.Error("expected the value to be read from the new map")
+	}
+
+	err = s.ForEach(func(claimID string, value []byte) error {
+		return s.Put(claimA, big)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_Closed(t *testing.T) {
+	s, _ := openTemp(t)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(claimA); err == nil {
+		t.Error("expected an error reading a closed store")
+	}
+	if err := s.Put(claimA, []byte("value")); err == nil {
+		t.Error("expected an error writing to a closed store")
+	}
+}
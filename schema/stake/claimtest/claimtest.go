@@ -0,0 +1,178 @@
+// Package claimtest builds channel and stream claims signed with a fresh key, so packages that verify claim signatures
+// can test against real values instead of hand-built fixtures.
+package claimtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+	"github.com/lbryio/lbry.go/v2/schema/keys"
+	"github.com/lbryio/lbry.go/v2/schema/stake"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/golang/protobuf/proto"
+	pb "github.com/lbryio/types/v2/go"
+)
+
+// Blockchain is the network the claims are decoded for
+const Blockchain = "lbrycrd_main"
+
+// SignedPair is a channel claim and a stream claim signed by that channel
+type SignedPair struct {
+	// PrivateKey is the channel's signing key
+	PrivateKey *btcec.PrivateKey
+	// PrivateKeyPEM is the signing key in the PEM format the SDK exports channels with
+	PrivateKeyPEM string
+
+	Channel    *stake.StakeHelper
+	ChannelHex string
+	ChannelID  string
+
+	Stream    *stake.StakeHelper
+	StreamHex string
+	StreamID  string
+	// FirstInputTxID is the first input of the stream's claim transaction. The signature covers it, so verifiers need it.
+	FirstInputTxID string
+}
+
+// NewSignedPair returns a new channel with a random key and a stream signed by it. The claim ids and the first input
+// are random too, so every pair is different.
+func NewSignedPair() (*SignedPair, error) {
+	privateKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	publicKey, err := keys.PublicKeyToDER(privateKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	der, err := keys.PrivateKeyToDER(privateKey)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	var privatePEM bytes.Buffer
+	err = pem.Encode(&privatePEM, &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	channel := &stake.StakeHelper{
+		Claim: &pb.Claim{
+			Title: "Test channel",
+			Type:  &pb.Claim_Channel{Channel: &pb.Channel{PublicKey: publicKey}},
+		},
+		Version: stake.NoSig,
+	}
+	channelValue, err := channel.CompileValue()
+	if err != nil {
+		return nil, err
+	}
+	channel, err = stake.DecodeClaimBytes(channelValue, Blockchain)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &SignedPair{
+		PrivateKey:    privateKey,
+		PrivateKeyPEM: privatePEM.String(),
+		Channel:       channel,
+		ChannelHex:    hex.EncodeToString(channelValue),
+	}
+	p.ChannelID, err = randomClaimID()
+	if err != nil {
+		return nil, err
+	}
+	p.StreamID, err = randomClaimID()
+	if err != nil {
+		return nil, err
+	}
+	p.FirstInputTxID, err = randomTxID()
+	if err != nil {
+		return nil, err
+	}
+
+	sdHash := make([]byte, 48)
+	_, err = rand.Read(sdHash)
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	stream := &pb.Claim{
+		Title:       "Test stream",
+		Description: "A stream signed by the test channel",
+		Tags:        []string{"test"},
+		Type: &pb.Claim_Stream{Stream: &pb.Stream{
+			Source: &pb.Source{
+				SdHash:    sdHash,
+				Name:      "test.mp4",
+				MediaType: "video/mp4",
+				Size:      1024,
+			},
+		}},
+	}
+	p.Stream, p.StreamHex, err = p.Sign(stream, p.FirstInputTxID)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Sign signs claim with the channel's key, as if it were published in a transaction whose first input is
+// firstInputTxID, and returns it decoded and as a hex value. claim is not modified.
+func (p *SignedPair) Sign(claim *pb.Claim, firstInputTxID string) (*stake.StakeHelper, string, error) {
+	channelID, err := hex.DecodeString(p.ChannelID)
+	if err != nil {
+		return nil, "", errors.Err(err)
+	}
+	// the channel id is stored reversed in signed claims
+	for i, j := 0, len(channelID)-1; i < j; i, j = i+1, j-1 {
+		channelID[i], channelID[j] = channelID[j], channelID[i]
+	}
+
+	helper := stake.StakeHelper{
+		Claim:   proto.Clone(claim).(*pb.Claim),
+		ClaimID: channelID,
+		Version: stake.WithSig,
+	}
+	signature, err := stake.Sign(*p.PrivateKey, *p.Channel, helper, firstInputTxID)
+	if err != nil {
+		return nil, "", err
+	}
+	helper.Signature, err = signature.LBRYSDKEncode()
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := helper.CompileValue()
+	if err != nil {
+		return nil, "", err
+	}
+	signed, err := stake.DecodeClaimBytes(value, Blockchain)
+	if err != nil {
+		return nil, "", err
+	}
+	return signed, hex.EncodeToString(value), nil
+}
+
+// Verify checks the stream's signature against the channel, the way a resolver would
+func (p *SignedPair) Verify() (bool, error) {
+	return p.Stream.ValidateClaimSignature(p.Channel, p.FirstInputTxID, p.ChannelID, Blockchain)
+}
+
+func randomTxID() (string, error) {
+	txid := make([]byte, 32)
+	_, err := rand.Read(txid)
+	if err != nil {
+		return "", errors.Err(err)
+	}
+	return hex.EncodeToString(txid), nil
+}
+
+func randomClaimID() (string, error) {
+	txid, err := randomTxID()
+	if err != nil {
+		return "", err
+	}
+	return stake.ClaimIDFromOutpoint(txid, 0)
+}
@@ -0,0 +1,103 @@
+package claimtest
+
+import (
+	"testing"
+
+	"github.com/lbryio/lbry.go/v2/schema/keys"
+	"github.com/lbryio/lbry.go/v2/schema/stake"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/lbryio/types/v2/go"
+)
+
+func TestNewSignedPair(t *testing.T) {
+	// the signature encoding used to break for short R or S values, so try enough pairs to hit one
+	for i := 0; i < 300; i++ {
+		p, err := NewSignedPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		valid, err := p.Verify()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatalf("pair %d does not verify", i)
+		}
+	}
+}
+
+func TestSignedPair_Values(t *testing.T) {
+	p, err := NewSignedPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel, err := stake.DecodeClaimHex(p.ChannelHex, Blockchain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := stake.DecodeClaimHex(p.StreamHex, Blockchain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := stream.ValidateClaimSignature(channel, p.FirstInputTxID, p.ChannelID, Blockchain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("stream hex does not verify against channel hex")
+	}
+
+	key, _ := keys.ExtractKeyFromPem(p.PrivateKeyPEM)
+	if !key.ToECDSA().Equal(p.PrivateKey.ToECDSA()) {
+		t.Error("PEM does not hold the private key")
+	}
+}
+
+func TestSignedPair_Tampered(t *testing.T) {
+	p, err := NewSignedPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := randomTxID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := p.Stream.ValidateClaimSignature(p.Channel, other, p.ChannelID, Blockchain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Error("signature verified with the wrong first input")
+	}
+
+	claim := proto.Clone(p.Stream.Claim).(*pb.Claim)
+	claim.Title = "Changed"
+	resigned, _, err := p.Sign(claim, p.FirstInputTxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Stream.Claim.Title = "Changed"
+	p.Stream.Payload, err = proto.Marshal(p.Stream.Claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err = p.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Error("signature verified after the claim changed")
+	}
+
+	p.Stream = resigned
+	valid, err = p.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("re-signed claim does not verify")
+	}
+}
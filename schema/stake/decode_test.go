@@ -66,6 +66,13 @@ func TestStripSignature(t *testing.T) {
 	if hex.EncodeToString(noSig) != raw_claims[2] {
 		t.Error("failed to remove signature")
 	}
+	unsigned, err := claim.UnsignedValue()
+	if err != nil {
+		t.Error(err)
+	}
+	if hex.EncodeToString(unsigned) != raw_claims[2] {
+		t.Error("legacy unsigned value should not get a version byte")
+	}
 }
 
 func TestCreateChannelClaim(t *testing.T) {
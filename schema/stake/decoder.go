@@ -0,0 +1,157 @@
+package stake
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	legacy_pb "github.com/lbryio/types/v1/go"
+	pb "github.com/lbryio/types/v2/go"
+)
+
+// DecodeWorkers is the number of goroutines DecodeAll uses. 0 means one per CPU.
+var DecodeWorkers = 0
+
+// decoderSlabSize is how many helpers and messages a keeping Decoder allocates at once
+const decoderSlabSize = 64
+
+// Decoder decodes many claim values one after another, such as when scanning the chain. It reuses its helper, its
+// claim and legacy claim messages and its read buffer between calls, so the helper returned by Decode is only valid
+// until the next call. Use DecodeClaimBytes for helpers that need to be kept. A Decoder is not safe for concurrent use.
+type Decoder struct {
+	blockchainName string
+	// keep makes the decoder hand out a new helper and messages for every value, taken from slabs it allocates
+	// decoderSlabSize at a time, so the helpers stay valid. DecodeAll's workers use it. ReadDecode is not used then,
+	// since helpers point into the read buffer.
+	keep     bool
+	helpers  []StakeHelper
+	claims   []pb.Claim
+	legacies []legacy_pb.Claim
+	hash     hash.Hash
+	buf      []byte
+}
+
+// NewDecoder returns a decoder for claims on the given blockchain
+func NewDecoder(blockchainName string) *Decoder {
+	return &Decoder{blockchainName: blockchainName}
+}
+
+// Decode decodes a claim value the same way DecodeClaimBytes does
+func (d *Decoder) Decode(serialized []byte) (*StakeHelper, error) {
+	size := 1
+	if d.keep {
+		size = decoderSlabSize
+	}
+	if len(d.helpers) == 0 {
+		d.helpers = make([]StakeHelper, size)
+	}
+	if len(d.claims) == 0 {
+		d.claims = make([]pb.Claim, size)
+	}
+	if len(d.legacies) == 0 {
+		d.legacies = make([]legacy_pb.Claim, size)
+	}
+
+	helper, claim, legacy := &d.helpers[0], &d.claims[0], &d.legacies[0]
+	err := helper.load(serialized, false, claim, legacy)
+	if err != nil {
+		return decodeJSONClaimOr(serialized, err)
+	}
+	if d.keep {
+		// only what the helper points to is used up; a legacy value leaves the claim message unused, and the reverse
+		d.helpers = d.helpers[1:]
+		if helper.Claim == claim {
+			d.claims = d.claims[1:]
+		}
+		if helper.LegacyClaim == legacy {
+			d.legacies = d.legacies[1:]
+		}
+	}
+	return helper, nil
+}
+
+// ValueHash is the SHA256 of a raw claim value
+type ValueHash [sha256.Size]byte
+
+func (h ValueHash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// ReadDecode reads a value of n bytes from r, hashing it as it is read, then decodes it like Decode, for pipelines that
+// dedupe or index values streamed from a dump. Each value is read only once. The hash is returned even if the value
+// can't be decoded. The value is read into a buffer the decoder reuses, so like the helper it is only valid until the
+// next call. n can be at most MaxClaimScriptSize, since no larger value fits in a claim; a larger n is taken to be a
+// corrupt length and nothing is read.
+func (d *Decoder) ReadDecode(r io.Reader, n int) (*StakeHelper, ValueHash, error) {
+	var h ValueHash
+	if n < 0 || n > MaxClaimScriptSize {
+		return nil, h, errors.Err("claim value length %d is not between 0 and %d", n, MaxClaimScriptSize)
+	}
+	if d.hash == nil {
+		d.hash = sha256.New()
+	}
+	d.hash.Reset()
+	if cap(d.buf) < n {
+		d.buf = make([]byte, n)
+	}
+	value := d.buf[:n]
+	_, err := io.ReadFull(io.TeeReader(r, d.hash), value)
+	if err != nil {
+		return nil, h, errors.Err(err)
+	}
+	d.hash.Sum(h[:0])
+	helper, err := d.Decode(value)
+	return helper, h, err
+}
+
+// DecodeAll decodes claim values in parallel. The helpers are returned in the same order as the values. If any value
+// can't be decoded, its helper is nil and errs holds the error at the same index; otherwise errs is nil. Each worker
+// decodes with its own Decoder, which allocates helpers and messages in batches rather than one by one.
+func DecodeAll(values [][]byte, blockchainName string) (helpers []*StakeHelper, errs []error) {
+	helpers = make([]*StakeHelper, len(values))
+	var failed int32
+	errSlice := make([]error, len(values))
+
+	workers := DecodeWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(values) {
+		workers = len(values)
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d := &Decoder{blockchainName: blockchainName, keep: true}
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(values) {
+					return
+				}
+				helper, err := d.Decode(values[i])
+				if err != nil {
+					errSlice[i] = err
+					atomic.StoreInt32(&failed, 1)
+					continue
+				}
+				helpers[i] = helper
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed != 0 {
+		errs = errSlice
+	}
+	return helpers, errs
+}
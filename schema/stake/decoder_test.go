@@ -0,0 +1,164 @@
+package stake
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func decoderTestValues(t testing.TB) [][]byte {
+	var values [][]byte
+	for _, claimHex := range raw_claims {
+		value, err := hex.DecodeString(claimHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestDecoder(t *testing.T) {
+	d := NewDecoder("lbrycrd_main")
+	for i, value := range decoderTestValues(t) {
+		expected, err := DecodeClaimBytes(value, "lbrycrd_main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		helper, err := d.Decode(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(helper.Claim, expected.Claim) || helper.Version != expected.Version ||
+			!bytes.Equal(helper.Signature, expected.Signature) || (helper.LegacyClaim == nil) != (expected.LegacyClaim == nil) {
+			t.Errorf("claim %d: decoder result differs from DecodeClaimBytes", i)
+		}
+	}
+
+	_, err := d.Decode([]byte{0x00, 0xff, 0xff})
+	if err == nil {
+		t.Error("expected an error for an invalid value")
+	}
+}
+
+func TestDecodeAll(t *testing.T) {
+	values := decoderTestValues(t)
+	helpers, errs := DecodeAll(values, "lbrycrd_main")
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	for i, value := range values {
+		expected, _ := DecodeClaimBytes(value, "lbrycrd_main")
+		if !proto.Equal(helpers[i].Claim, expected.Claim) {
+			t.Errorf("claim %d is out of order or decoded differently", i)
+		}
+	}
+
+	values = append(values, []byte("not a claim"))
+	helpers, errs = DecodeAll(values, "lbrycrd_main")
+	last := len(values) - 1
+	if errs == nil || errs[last] == nil || helpers[last] != nil {
+		t.Error("expected an error for the last value only")
+	}
+	if errs[0] != nil || helpers[0] == nil {
+		t.Error("a bad value should not affect the others")
+	}
+}
+
+func BenchmarkDecodeClaimBytes(b *testing.B) {
+	values := decoderTestValues(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = DecodeClaimBytes(values[i%len(values)], "lbrycrd_main")
+	}
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	values := decoderTestValues(b)
+	d := NewDecoder("lbrycrd_main")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = d.Decode(values[i%len(values)])
+	}
+}
+
+func BenchmarkDecodeAll(b *testing.B) {
+	values := decoderTestValues(b)
+	batch := make([][]byte, 1000)
+	for i := range batch {
+		batch[i] = values[i%len(values)]
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = DecodeAll(batch, "lbrycrd_main")
+	}
+}
+
+func TestDecoder_ReadDecode(t *testing.T) {
+	values := decoderTestValues(t)
+	d := NewDecoder("lbrycrd_main")
+	invalid := []byte("not a claim")
+	var dump bytes.Buffer
+	for _, value := range values {
+		dump.Write(value)
+	}
+	dump.Write(invalid)
+
+	for i, value := range values {
+		expected, _ := DecodeClaimBytes(value, "lbrycrd_main")
+		sum := ValueHash(sha256.Sum256(value))
+		helper, h, err := d.ReadDecode(&dump, len(value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h != sum || !proto.Equal(helper.Claim, expected.Claim) {
+			t.Errorf("claim %d: ReadDecode returned %s, expected %s", i, h, sum)
+		}
+	}
+
+	_, h, err := d.ReadDecode(&dump, len(invalid))
+	if err == nil || h != sha256.Sum256(invalid) {
+		t.Error("expected an error and the hash for an invalid value")
+	}
+	_, _, err = d.ReadDecode(&dump, 1)
+	if err == nil {
+		t.Error("expected an error reading past the end of the dump")
+	}
+
+	for _, n := range []int{-1, MaxClaimScriptSize + 1, math.MaxInt32} {
+		dump.Write(values[0])
+		_, _, err = d.ReadDecode(&dump, n)
+		if err == nil {
+			t.Errorf("expected an error for length %d", n)
+		}
+		if dump.Len() != len(values[0]) {
+			t.Errorf("length %d: nothing should be read", n)
+		}
+		dump.Reset()
+	}
+}
+
+func BenchmarkDecoder_ReadDecode(b *testing.B) {
+	values := decoderTestValues(b)
+	var dump bytes.Buffer
+	for _, value := range values {
+		dump.Write(value)
+	}
+	raw := dump.Bytes()
+	d := NewDecoder("lbrycrd_main")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := bytes.NewReader(raw)
+		for _, value := range values {
+			_, _, _ = d.ReadDecode(r, len(value))
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package stake
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/lbryio/types/v2/go"
+)
+
+// ClaimDiff is what has to change to turn a published claim into the desired one
+type ClaimDiff struct {
+	// Changes holds the fields of the desired claim that differ from the published one. Unchanged fields are left unset,
+	// as are fields the desired claim clears, since an unset field can't be told apart from a cleared one.
+	Changes *pb.Claim
+	// Fields names every changed or cleared field, e.g. "title", "tags" or "stream.fee"
+	Fields []string
+}
+
+// NeedsUpdate returns true if the claim has to be updated on chain
+func (d *ClaimDiff) NeedsUpdate() bool {
+	return len(d.Fields) > 0
+}
+
+// Changed returns true if the named field changed
+func (d *ClaimDiff) Changed(field string) bool {
+	for _, f := range d.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff compares a published claim with the claim it should be, so an update can be skipped when nothing changed and
+// otherwise carry only what did. desired should be the whole claim, e.g. a proto.Clone of the published claim with
+// new metadata, since fields missing from it count as cleared. Tags are compared without regard to order or case.
+func Diff(previous, desired *StakeHelper) (*ClaimDiff, error) {
+	if previous == nil || previous.Claim == nil || desired == nil || desired.Claim == nil {
+		return nil, errors.Err("both the previous and the desired value must be claims")
+	}
+	prev, want := previous.Claim, desired.Claim
+	if claimType(prev) != claimType(want) {
+		return nil, errors.Err("claim type can't change from %s to %s", claimType(prev), claimType(want))
+	}
+
+	d := &ClaimDiff{Changes: &pb.Claim{}}
+	if prev.GetTitle() != want.GetTitle() {
+		d.Changes.Title = want.GetTitle()
+		d.Fields = append(d.Fields, "title")
+	}
+	if prev.GetDescription() != want.GetDescription() {
+		d.Changes.Description = want.GetDescription()
+		d.Fields = append(d.Fields, "description")
+	}
+	if !proto.Equal(prev.GetThumbnail(), want.GetThumbnail()) {
+		d.Changes.Thumbnail = want.GetThumbnail()
+		d.Fields = append(d.Fields, "thumbnail")
+	}
+	if !sameTags(prev.GetTags(), want.GetTags()) {
+		d.Changes.Tags = want.GetTags()
+		d.Fields = append(d.Fields, "tags")
+	}
+	if !sameLanguages(prev.GetLanguages(), want.GetLanguages()) {
+		d.Changes.Languages = want.GetLanguages()
+		d.Fields = append(d.Fields, "languages")
+	}
+	if !sameLocations(prev.GetLocations(), want.GetLocations()) {
+		d.Changes.Locations = want.GetLocations()
+		d.Fields = append(d.Fields, "locations")
+	}
+
+	switch claimType(want) {
+	case "stream":
+		d.diffStream(prev.GetStream(), want.GetStream())
+	case "channel":
+		d.diffChannel(prev.GetChannel(), want.GetChannel())
+	case "collection":
+		if !proto.Equal(prev.GetCollection(), want.GetCollection()) {
+			d.Changes.Type = want.Type
+			d.Fields = append(d.Fields, "collection")
+		}
+	case "repost":
+		if !proto.Equal(prev.GetRepost(), want.GetRepost()) {
+			d.Changes.Type = want.Type
+			d.Fields = append(d.Fields, "repost")
+		}
+	}
+	return d, nil
+}
+
+func (d *ClaimDiff) diffStream(prev, want *pb.Stream) {
+	changes := &pb.Stream{}
+	fields := len(d.Fields)
+	if !proto.Equal(prev.GetSource(), want.GetSource()) {
+		changes.Source = want.GetSource()
+		d.Fields = append(d.Fields, "stream.source")
+	}
+	if prev.GetAuthor() != want.GetAuthor() {
+		changes.Author = want.GetAuthor()
+		d.Fields = append(d.Fields, "stream.author")
+	}
+	if prev.GetLicense() != want.GetLicense() {
+		changes.License = want.GetLicense()
+		d.Fields = append(d.Fields, "stream.license")
+	}
+	if prev.GetLicenseUrl() != want.GetLicenseUrl() {
+		changes.LicenseUrl = want.GetLicenseUrl()
+		d.Fields = append(d.Fields, "stream.license_url")
+	}
+	if prev.GetReleaseTime() != want.GetReleaseTime() {
+		changes.ReleaseTime = want.GetReleaseTime()
+		d.Fields = append(d.Fields, "stream.release_time")
+	}
+	if !proto.Equal(prev.GetFee(), want.GetFee()) {
+		changes.Fee = want.GetFee()
+		d.Fields = append(d.Fields, "stream.fee")
+	}
+	if !proto.Equal(&pb.Stream{Type: prev.GetType()}, &pb.Stream{Type: want.GetType()}) {
+		changes.Type = want.GetType()
+		d.Fields = append(d.Fields, "stream.type")
+	}
+	if len(d.Fields) > fields {
+		d.Changes.Type = &pb.Claim_Stream{Stream: changes}
+	}
+}
+
+func (d *ClaimDiff) diffChannel(prev, want *pb.Channel) {
+	changes := &pb.Channel{}
+	fields := len(d.Fields)
+	if string(prev.GetPublicKey()) != string(want.GetPublicKey()) {
+		changes.PublicKey = want.GetPublicKey()
+		d.Fields = append(d.Fields, "channel.public_key")
+	}
+	if prev.GetEmail() != want.GetEmail() {
+		changes.Email = want.GetEmail()
+		d.Fields = append(d.Fields, "channel.email")
+	}
+	if prev.GetWebsiteUrl() != want.GetWebsiteUrl() {
+		changes.WebsiteUrl = want.GetWebsiteUrl()
+		d.Fields = append(d.Fields, "channel.website_url")
+	}
+	if !proto.Equal(prev.GetCover(), want.GetCover()) {
+		changes.Cover = want.GetCover()
+		d.Fields = append(d.Fields, "channel.cover")
+	}
+	if !proto.Equal(prev.GetFeatured(), want.GetFeatured()) {
+		changes.Featured = want.GetFeatured()
+		d.Fields = append(d.Fields, "channel.featured")
+	}
+	if len(d.Fields) > fields {
+		d.Changes.Type = &pb.Claim_Channel{Channel: changes}
+	}
+}
+
+func claimType(claim *pb.Claim) string {
+	switch claim.GetType().(type) {
+	case *pb.Claim_Stream:
+		return "stream"
+	case *pb.Claim_Channel:
+		return "channel"
+	case *pb.Claim_Collection:
+		return "collection"
+	case *pb.Claim_Repost:
+		return "repost"
+	}
+	return "unknown"
+}
+
+// sameTags compares tags the way the SDK stores them, trimmed and lowercased, ignoring order and duplicates
+func sameTags(a, b []string) bool {
+	return strings.Join(normalizeTags(a), "\x00") == strings.Join(normalizeTags(b), "\x00")
+}
+
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// sameLanguages compares languages in order, since the first one is the primary language
+func sameLanguages(a, b []*pb.Language) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameLocations(a, b []*pb.Location) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/lbryio/types/v2/go"
+)
+
+func diffTestStream() *StakeHelper {
+	claim := newStreamClaim()
+	claim.Title = "title"
+	claim.Description = "description"
+	claim.Tags = []string{"gaming", "Music"}
+	claim.GetStream().Author = "author"
+	claim.GetStream().ReleaseTime = 1000
+	claim.GetStream().Source = &pb.Source{SdHash: []byte{1, 2, 3}}
+	return &StakeHelper{Claim: claim}
+}
+
+func TestDiff_NoChange(t *testing.T) {
+	previous := diffTestStream()
+	desired := &StakeHelper{Claim: proto.Clone(previous.Claim).(*pb.Claim)}
+	desired.Claim.Tags = []string{"music ", "gaming", "gaming"}
+
+	diff, err := Diff(previous, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.NeedsUpdate() {
+		t.Errorf("expected no update, got changes to %v", diff.Fields)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous := diffTestStream()
+	desired := &StakeHelper{Claim: proto.Clone(previous.Claim).(*pb.Claim)}
+	desired.Claim.Title = "new title"
+	desired.Claim.Description = ""
+	desired.Claim.GetStream().ReleaseTime = 2000
+
+	diff, err := Diff(previous, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"title", "description", "stream.release_time"}
+	if len(diff.Fields) != len(expected) {
+		t.Fatalf("expected changes to %v, got %v", expected, diff.Fields)
+	}
+	for _, field := range expected {
+		if !diff.Changed(field) {
+			t.Errorf("expected %s to change", field)
+		}
+	}
+
+	changes := diff.Changes
+	if changes.GetTitle() != "new title" || changes.GetStream().GetReleaseTime() != 2000 {
+		t.Errorf("changes are missing the new values: %v", changes)
+	}
+	if len(changes.GetTags()) > 0 || changes.GetStream().GetAuthor() != "" || changes.GetStream().GetSource() != nil {
+		t.Errorf("unchanged fields should be left out: %v", changes)
+	}
+}
+
+func TestDiff_TypeChange(t *testing.T) {
+	channel := newChannelClaim()
+	_, err := Diff(diffTestStream(), &StakeHelper{Claim: channel})
+	if err == nil {
+		t.Error("expected an error when the claim type changes")
+	}
+}
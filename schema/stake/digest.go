@@ -0,0 +1,130 @@
+package stake
+
+import (
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+// DigestFunc hashes the concatenation of its arguments
+type DigestFunc func(data ...[]byte) []byte
+
+type DigestAlgorithm string
+
+const (
+	DigestSHA256 = DigestAlgorithm("sha256")
+	// DigestHash160 is ripemd160(sha256(data)), used for claim IDs
+	DigestHash160 = DigestAlgorithm("hash160")
+)
+
+// ClaimIDDigest is the algorithm used to derive a claim ID from its outpoint
+const ClaimIDDigest = DigestHash160
+
+// digestRegistry is never modified once stored. Registering copies it, so decoding reads it without taking a lock.
+type digestRegistry struct {
+	digests    map[DigestAlgorithm]DigestFunc
+	sigDigests map[version]DigestAlgorithm
+}
+
+var (
+	digestMu sync.Mutex   // serializes registrations
+	registry atomic.Value // *digestRegistry
+)
+
+func init() {
+	registry.Store(&digestRegistry{
+		digests:    map[DigestAlgorithm]DigestFunc{DigestSHA256: sha256Digest, DigestHash160: hash160Digest},
+		sigDigests: map[version]DigestAlgorithm{WithSig: DigestSHA256},
+	})
+}
+
+func loadDigests() *digestRegistry {
+	return registry.Load().(*digestRegistry)
+}
+
+func (r *digestRegistry) clone() *digestRegistry {
+	c := &digestRegistry{
+		digests:    make(map[DigestAlgorithm]DigestFunc, len(r.digests)+1),
+		sigDigests: make(map[version]DigestAlgorithm, len(r.sigDigests)+1),
+	}
+	for alg, f := range r.digests {
+		c.digests[alg] = f
+	}
+	for v, alg := range r.sigDigests {
+		c.sigDigests[v] = alg
+	}
+	return c
+}
+
+// RegisterDigest makes a digest algorithm available under the given name, replacing any previous registration
+func RegisterDigest(alg DigestAlgorithm, f DigestFunc) {
+	digestMu.Lock()
+	defer digestMu.Unlock()
+	r := loadDigests().clone()
+	r.digests[alg] = f
+	registry.Store(r)
+}
+
+// RegisterSignatureVersion declares that claims with the given version byte carry a signature over a digest computed
+// with alg. The signature layout (20 byte channel claim ID followed by a 64 byte signature) is the same for every
+// version.
+func RegisterSignatureVersion(v byte, alg DigestAlgorithm) error {
+	if version(v) == NoSig || version(v) == UNKNOWN {
+		return errors.Err("version %d is reserved", v)
+	}
+	digestMu.Lock()
+	defer digestMu.Unlock()
+	r := loadDigests().clone()
+	if _, ok := r.digests[alg]; !ok {
+		return errors.Err("unknown digest algorithm %s", alg)
+	}
+	r.sigDigests[version(v)] = alg
+	registry.Store(r)
+	return nil
+}
+
+// Digest returns the digest function registered for alg
+func Digest(alg DigestAlgorithm) (DigestFunc, error) {
+	f, ok := loadDigests().digests[alg]
+	if !ok {
+		return nil, errors.Err("unknown digest algorithm %s", alg)
+	}
+	return f, nil
+}
+
+// SignatureDigest returns the digest function used for signatures on claims with the given version
+func SignatureDigest(v version) (DigestFunc, error) {
+	r := loadDigests()
+	alg, ok := r.sigDigests[v]
+	if !ok {
+		return nil, errors.Err("no signature digest registered for claim version %d", v)
+	}
+	f, ok := r.digests[alg]
+	if !ok {
+		return nil, errors.Err("unknown digest algorithm %s", alg)
+	}
+	return f, nil
+}
+
+func isSignedVersion(v version) bool {
+	_, ok := loadDigests().sigDigests[v]
+	return ok
+}
+
+func sha256Digest(data ...[]byte) []byte {
+	h := sha256.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func hash160Digest(data ...[]byte) []byte {
+	r := ripemd160.New()
+	r.Write(sha256Digest(data...))
+	return r.Sum(nil)
+}
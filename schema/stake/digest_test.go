@@ -0,0 +1,73 @@
+package stake
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var digestVectors = []struct {
+	alg      DigestAlgorithm
+	input    []string
+	expected string
+}{
+	{DigestSHA256, []string{"abc"}, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	{DigestSHA256, []string{"a", "b", "c"}, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	{DigestHash160, []string{"abc"}, "bb1be98c142444d7a56aa3981c3942a978e4dc33"},
+}
+
+func TestDigestVectors(t *testing.T) {
+	for _, v := range digestVectors {
+		digest, err := Digest(v.alg)
+		assert.NilError(t, err)
+		var input [][]byte
+		for _, in := range v.input {
+			input = append(input, []byte(in))
+		}
+		assert.Equal(t, hex.EncodeToString(digest(input...)), v.expected, string(v.alg))
+	}
+}
+
+// signature digest vectors by claim version. Version 1 is the signed claim from TestV2ValidateClaimSignature.
+var signatureDigestVectors = []struct {
+	version  byte
+	alg      DigestAlgorithm
+	claimHex string
+}{
+	{1, DigestSHA256, "015cb78e424a34fbf79b67f9107430427aa62373e69b4998a29ecec8f14a9e0a213a043ced8064c069d7e464b5fd3ccb92b45bd59b15c0e1bb27e3c366d43f86a9a6b5ad42647a1aad69a73ac50b19ae3ec978c2c70aa2010a99010a301c662f19abc461e7eddecf165adfa7fca569e209773f3db31241c1e297f0a8d5b3e4768828b065fbeb1d6776f61073f6121b3031202d20556e6d6173746572656420496d70756c7365732e377a187a22146170706c69636174696f6e2f782d6578742d377a32302eb61ea475017e28c013616a56c1219ba90dc35fffff453d9675146f648f66634e0d1516528d37aba9f5801229d9f2181a044e6f6e6542087465737420707562520062020801"},
+}
+
+func TestSignatureDigestVectors(t *testing.T) {
+	for _, v := range signatureDigestVectors {
+		claim, err := DecodeClaimHex(v.claimHex, "lbrycrd_main")
+		assert.NilError(t, err)
+		assert.Equal(t, claim.Version, version(v.version))
+
+		expected, err := Digest(v.alg)
+		assert.NilError(t, err)
+		got, err := claim.getClaimSignatureDigest([]byte("payload"))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, expected([]byte("payload")))
+	}
+}
+
+func TestRegisterSignatureVersion(t *testing.T) {
+	assert.Assert(t, RegisterSignatureVersion(0, DigestSHA256) != nil)
+	assert.Assert(t, RegisterSignatureVersion(3, DigestAlgorithm("nope")) != nil)
+
+	defer func(r *digestRegistry) { registry.Store(r) }(loadDigests())
+	RegisterDigest("test-reversed-sha256", func(data ...[]byte) []byte {
+		return reverseBytes(sha256Digest(data...))
+	})
+	assert.NilError(t, RegisterSignatureVersion(3, "test-reversed-sha256"))
+
+	assert.Equal(t, getVersionFromByte(3), version(3))
+	digest, err := SignatureDigest(3)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, digest([]byte("abc")), reverseBytes(sha256Digest([]byte("abc"))))
+
+	_, err = SignatureDigest(4)
+	assert.Assert(t, err != nil)
+	assert.Equal(t, getVersionFromByte(4), UNKNOWN)
+}
@@ -0,0 +1,105 @@
+package stake
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// Enricher fills in claim metadata before it is published, e.g. from the file or from an external service
+type Enricher interface {
+	Enrich(ctx context.Context, claim *StakeHelper) error
+}
+
+// EnricherFunc lets a function be used as an Enricher
+type EnricherFunc func(ctx context.Context, claim *StakeHelper) error
+
+func (f EnricherFunc) Enrich(ctx context.Context, claim *StakeHelper) error {
+	return f(ctx, claim)
+}
+
+// Chain runs enrichers in order, so later ones see what earlier ones set. It stops at the first error, or when ctx
+// is done.
+type Chain []Enricher
+
+func (c Chain) Enrich(ctx context.Context, claim *StakeHelper) error {
+	if err := checkEnrichable(claim); err != nil {
+		return err
+	}
+	for i, enricher := range c {
+		if err := ctx.Err(); err != nil {
+			return errors.Err(err)
+		}
+		if err := enricher.Enrich(ctx, claim); err != nil {
+			return errors.Prefix("enricher "+strconv.Itoa(i), err)
+		}
+	}
+	return nil
+}
+
+// checkEnrichable returns an error if claim is not a claim, e.g. a support
+func checkEnrichable(claim *StakeHelper) error {
+	if claim == nil || claim.Claim == nil {
+		return errors.Err("only claims can be enriched")
+	}
+	return nil
+}
+
+// ReleaseTimeFromFile sets a stream's release time to the modification time of the file at Path, unless the release
+// time is already set
+type ReleaseTimeFromFile struct {
+	Path string
+}
+
+func (r ReleaseTimeFromFile) Enrich(ctx context.Context, claim *StakeHelper) error {
+	if err := checkEnrichable(claim); err != nil {
+		return err
+	}
+	stream := claim.Claim.GetStream()
+	if stream == nil || stream.GetReleaseTime() != 0 {
+		return nil
+	}
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		return errors.Err(err)
+	}
+	stream.ReleaseTime = info.ModTime().Unix()
+	return nil
+}
+
+// TagsFromTitle adds a tag for every keyword in the claim's title. Tags the claim already has are not added again.
+type TagsFromTitle struct {
+	// Keywords maps a lowercase word to the tag it adds, e.g. "speedrun" to "gaming"
+	Keywords map[string]string
+	// MaxTags stops adding tags once the claim has this many. 0 means no limit.
+	MaxTags int
+}
+
+func (t TagsFromTitle) Enrich(ctx context.Context, claim *StakeHelper) error {
+	if err := checkEnrichable(claim); err != nil {
+		return err
+	}
+	has := make(map[string]bool, len(claim.Claim.Tags))
+	for _, tag := range normalizeTags(claim.Claim.Tags) {
+		has[tag] = true
+	}
+	words := strings.FieldsFunc(strings.ToLower(claim.Claim.GetTitle()), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if t.MaxTags > 0 && len(claim.Claim.Tags) >= t.MaxTags {
+			break
+		}
+		tag, ok := t.Keywords[word]
+		if !ok || has[strings.ToLower(tag)] {
+			continue
+		}
+		has[strings.ToLower(tag)] = true
+		claim.Claim.Tags = append(claim.Claim.Tags, tag)
+	}
+	return nil
+}
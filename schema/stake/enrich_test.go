@@ -0,0 +1,118 @@
+package stake
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+
+	pb "github.com/lbryio/types/v2/go"
+)
+
+func TestChain(t *testing.T) {
+	file, err := ioutil.TempFile("", "enrich")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+	modified := time.Unix(1600000000, 0)
+	err = os.Chtimes(file.Name(), modified, modified)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claim := &StakeHelper{Claim: newStreamClaim()}
+	claim.Claim.Title = "My Minecraft Speedrun (World Record!)"
+	claim.Claim.Tags = []string{"Gaming"}
+
+	chain := Chain{
+		ReleaseTimeFromFile{Path: file.Name()},
+		TagsFromTitle{Keywords: map[string]string{"minecraft": "minecraft", "speedrun": "gaming", "record": "world record"}},
+		EnricherFunc(func(ctx context.Context, claim *StakeHelper) error {
+			claim.Claim.Description = "tags: " + claim.Claim.Tags[len(claim.Claim.Tags)-1]
+			return nil
+		}),
+	}
+	err = chain.Enrich(context.Background(), claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Claim.GetStream().ReleaseTime != modified.Unix() {
+		t.Errorf("expected release time %d, got %d", modified.Unix(), claim.Claim.GetStream().ReleaseTime)
+	}
+	if expected := []string{"Gaming", "minecraft", "world record"}; !reflect.DeepEqual(claim.Claim.Tags, expected) {
+		t.Errorf("expected tags %v, got %v", expected, claim.Claim.Tags)
+	}
+	if claim.Claim.Description != "tags: world record" {
+		t.Error("enrichers should run in order")
+	}
+
+	// a release time that is already set is kept
+	claim.Claim.GetStream().ReleaseTime = 1
+	err = ReleaseTimeFromFile{Path: file.Name()}.Enrich(context.Background(), claim)
+	if err != nil || claim.Claim.GetStream().ReleaseTime != 1 {
+		t.Errorf("release time was overwritten, err %v", err)
+	}
+}
+
+func TestChain_Errors(t *testing.T) {
+	failed := errors.Base("failed")
+	ran := false
+	chain := Chain{
+		EnricherFunc(func(ctx context.Context, claim *StakeHelper) error { return failed }),
+		EnricherFunc(func(ctx context.Context, claim *StakeHelper) error { ran = true; return nil }),
+	}
+	err := chain.Enrich(context.Background(), &StakeHelper{Claim: newStreamClaim()})
+	if !errors.Is(err, failed) || ran {
+		t.Errorf("expected the chain to stop at the first error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = Chain{TagsFromTitle{}}.Enrich(ctx, &StakeHelper{Claim: newStreamClaim()})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	err = Chain{}.Enrich(context.Background(), &StakeHelper{Support: &pb.Support{}})
+	if err == nil {
+		t.Error("expected an error for a support")
+	}
+
+	err = ReleaseTimeFromFile{Path: "/does/not/exist"}.Enrich(context.Background(), &StakeHelper{Claim: newStreamClaim()})
+	if err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestTagsFromTitle_MaxTags(t *testing.T) {
+	claim := &StakeHelper{Claim: newStreamClaim()}
+	claim.Claim.Title = "one two three"
+	err := TagsFromTitle{Keywords: map[string]string{"one": "1", "two": "2", "three": "3"}, MaxTags: 2}.Enrich(context.Background(), claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(claim.Claim.Tags, []string{"1", "2"}) {
+		t.Errorf("expected two tags, got %v", claim.Claim.Tags)
+	}
+}
+
+func TestEnrichers_NotClaims(t *testing.T) {
+	enrichers := map[string]Enricher{
+		"ReleaseTimeFromFile": ReleaseTimeFromFile{Path: "/does/not/exist"},
+		"TagsFromTitle":       TagsFromTitle{Keywords: map[string]string{"one": "1"}},
+	}
+	for name, enricher := range enrichers {
+		for _, claim := range []*StakeHelper{nil, {}, {Support: &pb.Support{}}} {
+			err := enricher.Enrich(context.Background(), claim)
+			if err == nil {
+				t.Errorf("%s: expected an error for %+v", name, claim)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package stake
+
+import (
+	"fmt"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// Claimtrie limits enforced by lbrycrd. Check against these instead of hard-coding the numbers.
+const (
+	// MaxClaimNameSize is the longest claim name, in bytes
+	MaxClaimNameSize = 255
+	// MaxClaimScriptSize is the largest claim script, which is the part of an output script before the payout script.
+	// The script holds the name as well as the value, so the largest value depends on the name; see MaxClaimValueSize.
+	MaxClaimScriptSize = 8192
+	// ProportionalDelayFactor delays the takeover of a name by one block for every this many blocks the controlling
+	// claim has controlled it
+	ProportionalDelayFactor = 32
+	// MaxTakeoverDelay is the longest a takeover is delayed, in blocks. It is about a week.
+	MaxTakeoverDelay = 4032
+)
+
+// claimIDSize is the size of the claim ID pushed by update scripts
+const claimIDSize = 20
+
+// ValidateClaimName returns an error if lbrycrd would reject the name
+func ValidateClaimName(name string) error {
+	if message := claimNameProblem(name); message != "" {
+		return errors.Err("claim name " + message)
+	}
+	return nil
+}
+
+// IsValidClaimName returns true if lbrycrd accepts the name
+func IsValidClaimName(name string) bool {
+	return claimNameProblem(name) == ""
+}
+
+// claimNameProblem returns why lbrycrd would reject the name, or "" if it would not
+func claimNameProblem(name string) string {
+	if name == "" {
+		return "is empty"
+	}
+	if len(name) > MaxClaimNameSize {
+		return fmt.Sprintf("is %d bytes long, lbrycrd allows %d", len(name), MaxClaimNameSize)
+	}
+	return ""
+}
+
+// MaxClaimValueSize returns the largest value that fits in a claim script with the given name. Update scripts also
+// hold the claim ID, which leaves less room for the value.
+func MaxClaimValueSize(name string, update bool) int {
+	// values this large are pushed with OP_PUSHDATA2, which takes 3 bytes before the data
+	return MaxClaimScriptSize - claimScriptOverhead(name, update) - 3
+}
+
+// ValidateClaimSize returns an error if the name or the compiled value, as returned by CompileValue, are too large
+// for lbrycrd to accept a claim script holding them
+func ValidateClaimSize(name string, value []byte, update bool) error {
+	if err := ValidateClaimName(name); err != nil {
+		return err
+	}
+	if size := claimScriptSize(name, len(value), update); size > MaxClaimScriptSize {
+		return errors.Err("claim script would be %d bytes, lbrycrd allows %d; the value is %d bytes and can be at most %d",
+			size, MaxClaimScriptSize, len(value), MaxClaimValueSize(name, update))
+	}
+	return nil
+}
+
+// claimScriptSize returns the size of the script OP_CLAIM_NAME <name> <value> OP_2DROP OP_DROP, or of
+// OP_UPDATE_CLAIM <name> <claim id> <value> OP_2DROP OP_2DROP if update is true
+func claimScriptSize(name string, valueSize int, update bool) int {
+	return claimScriptOverhead(name, update) + pushSize(valueSize)
+}
+
+// claimScriptOverhead returns the size of a claim script without its value
+func claimScriptOverhead(name string, update bool) int {
+	size := 1 + pushSize(len(name)) + 2
+	if update {
+		size += pushSize(claimIDSize)
+	}
+	return size
+}
+
+// pushSize returns the size of the script instruction that pushes n bytes of data
+func pushSize(n int) int {
+	switch {
+	case n <= 75:
+		return 1 + n
+	case n <= 0xff:
+		return 2 + n
+	case n <= 0xffff:
+		return 3 + n
+	default:
+		return 5 + n
+	}
+}
+
+// TakeoverDelay returns how many blocks a new claim has to wait before it can take over a name whose controlling
+// claim took it over blocksControlled blocks ago
+func TakeoverDelay(blocksControlled int32) int32 {
+	if blocksControlled <= 0 {
+		return 0
+	}
+	delay := blocksControlled / ProportionalDelayFactor
+	if delay > MaxTakeoverDelay {
+		return MaxTakeoverDelay
+	}
+	return delay
+}
@@ -0,0 +1,87 @@
+package stake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+func TestIsValidClaimName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"":                                      false,
+		"a":                                     true,
+		"@channel":                              true,
+		strings.Repeat("n", MaxClaimNameSize):   true,
+		strings.Repeat("n", MaxClaimNameSize+1): false,
+		strings.Repeat("é", MaxClaimNameSize/2): true,
+		strings.Repeat("é", MaxClaimNameSize/2+1): false,
+	} {
+		if IsValidClaimName(name) != valid {
+			t.Errorf("expected IsValidClaimName to be %t for a name of %d bytes", valid, len(name))
+		}
+		if (ValidateClaimName(name) == nil) != valid {
+			t.Errorf("expected ValidateClaimName to agree with IsValidClaimName for a name of %d bytes", len(name))
+		}
+	}
+}
+
+func TestClaimScriptSize(t *testing.T) {
+	claimID := make([]byte, claimIDSize)
+	for _, name := range []string{"a", strings.Repeat("n", 80), strings.Repeat("n", MaxClaimNameSize)} {
+		for _, update := range []bool{false, true} {
+			value := make([]byte, MaxClaimValueSize(name, update))
+			builder := txscript.NewScriptBuilder().AddOp(txscript.OP_NOP6).AddData([]byte(name))
+			if update {
+				builder = txscript.NewScriptBuilder().AddOp(txscript.OP_NOP8).AddData([]byte(name)).AddData(claimID)
+			}
+			// the script builder refuses pushes larger than standard scripts allow, so build the value push by hand
+			script, err := builder.Script()
+			if err != nil {
+				t.Fatal(err)
+			}
+			script = append(script, txscript.OP_PUSHDATA2, byte(len(value)), byte(len(value)>>8))
+			script = append(append(script, value...), txscript.OP_2DROP, txscript.OP_DROP)
+
+			if len(script) != MaxClaimScriptSize {
+				t.Errorf("expected the largest value to fill the script, got a script of %d bytes", len(script))
+			}
+			if size := claimScriptSize(name, len(value), update); size != len(script) {
+				t.Errorf("expected a script of %d bytes, computed %d", len(script), size)
+			}
+			if err := ValidateClaimSize(name, value, update); err != nil {
+				t.Error(err)
+			}
+			if err := ValidateClaimSize(name, append(value, 0), update); err == nil {
+				t.Errorf("expected a value of %d bytes to be too large", len(value)+1)
+			}
+		}
+	}
+	if err := ValidateClaimSize("", []byte{1}, false); err == nil {
+		t.Error("expected an empty name to be rejected")
+	}
+}
+
+func TestTakeoverDelay(t *testing.T) {
+	for blocks, delay := range map[int32]int32{
+		-1:                            0,
+		0:                             0,
+		31:                            0,
+		32:                            1,
+		1000:                          31,
+		MaxTakeoverDelay * 32:         MaxTakeoverDelay,
+		MaxTakeoverDelay*32 + 1000000: MaxTakeoverDelay,
+	} {
+		if got := TakeoverDelay(blocks); got != delay {
+			t.Errorf("expected a delay of %d after %d blocks, got %d", delay, blocks, got)
+		}
+	}
+}
+
+func TestLint_ClaimName(t *testing.T) {
+	helper := &StakeHelper{Claim: newStreamClaim()}
+	problems := helper.Lint(strings.Repeat("n", MaxClaimNameSize+1), Profile{Name: "loose"})
+	if len(problems) != 1 || problems[0].Field != "name" {
+		t.Errorf("expected a name that lbrycrd rejects to be a problem with any profile, got %v", problems)
+	}
+}
@@ -0,0 +1,146 @@
+package stake
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+// Profile bundles the metadata limits of a frontend, so a claim can be checked against what that frontend displays
+// well. Lengths are in characters, and a limit of 0 means no limit.
+type Profile struct {
+	Name                 string
+	MaxNameLength        int
+	MaxTitleLength       int
+	MaxDescriptionLength int
+	MaxTags              int
+	MaxTagLength         int
+	// StrictUTF8 rejects invalid UTF-8 and control characters. Descriptions may still contain newlines and tabs.
+	StrictUTF8 bool
+}
+
+var (
+	// ProfileMinimal only checks that text is valid UTF-8
+	ProfileMinimal = Profile{Name: "minimal", StrictUTF8: true}
+	// ProfileOdysee fits the odysee publish form
+	ProfileOdysee = Profile{
+		Name:                 "odysee",
+		MaxNameLength:        255,
+		MaxTitleLength:       200,
+		MaxDescriptionLength: 5000,
+		MaxTags:              20,
+		MaxTagLength:         50,
+		StrictUTF8:           true,
+	}
+	// ProfileSpeech fits spee.ch, whose short urls and image pages leave less room
+	ProfileSpeech = Profile{
+		Name:                 "spee.ch",
+		MaxNameLength:        64,
+		MaxTitleLength:       100,
+		MaxDescriptionLength: 1000,
+		MaxTags:              5,
+		MaxTagLength:         30,
+		StrictUTF8:           true,
+	}
+)
+
+var profiles = map[string]Profile{
+	ProfileMinimal.Name: ProfileMinimal,
+	ProfileOdysee.Name:  ProfileOdysee,
+	ProfileSpeech.Name:  ProfileSpeech,
+}
+
+// LookupProfile returns the profile with the given name
+func LookupProfile(name string) (Profile, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, errors.Err("unknown validation profile %q", name)
+	}
+	return profile, nil
+}
+
+// Problem is something in a claim that goes against a profile
+type Problem struct {
+	Field   string
+	Message string
+}
+
+func (p Problem) Error() string {
+	return p.Field + ": " + p.Message
+}
+
+// Lint checks the claim's metadata against the profile and returns every problem found. The name is the claim name
+// the claim is published under, and is also checked against the limits lbrycrd enforces; pass "" to skip checking it.
+func (c *StakeHelper) Lint(name string, profile Profile) []Problem {
+	var problems []Problem
+	check := func(field, value string, maxLength int, multiline bool) {
+		if profile.StrictUTF8 {
+			if message := checkText(value, multiline); message != "" {
+				problems = append(problems, Problem{field, message})
+				return
+			}
+		}
+		if length := utf8.RuneCountInString(value); maxLength > 0 && length > maxLength {
+			problems = append(problems, Problem{field, fmt.Sprintf("is %d characters long, %s allows %d", length, profile.Name, maxLength)})
+		}
+	}
+
+	if name != "" {
+		if message := claimNameProblem(name); message != "" {
+			problems = append(problems, Problem{"name", message})
+		} else {
+			check("name", name, profile.MaxNameLength, false)
+		}
+	}
+	if c.Claim == nil {
+		return problems
+	}
+	check("title", c.Claim.GetTitle(), profile.MaxTitleLength, false)
+	check("description", c.Claim.GetDescription(), profile.MaxDescriptionLength, true)
+
+	tags := c.Claim.GetTags()
+	if profile.MaxTags > 0 && len(tags) > profile.MaxTags {
+		problems = append(problems, Problem{"tags", fmt.Sprintf("has %d tags, %s allows %d", len(tags), profile.Name, profile.MaxTags)})
+	}
+	for i, tag := range tags {
+		check(fmt.Sprintf("tags[%d]", i), tag, profile.MaxTagLength, false)
+	}
+
+	if stream := c.Claim.GetStream(); stream != nil {
+		check("stream.author", stream.GetAuthor(), 0, false)
+		check("stream.license", stream.GetLicense(), 0, false)
+	}
+	return problems
+}
+
+// Validate checks the claim's metadata against the profile and returns an error describing the problems, if any
+func (c *StakeHelper) Validate(name string, profile Profile) error {
+	problems := c.Lint(name, profile)
+	if len(problems) == 0 {
+		return nil
+	}
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.Error()
+	}
+	return errors.Err("claim does not fit the %s profile: %s", profile.Name, strings.Join(messages, "; "))
+}
+
+// checkText returns why the text is not clean UTF-8, or "" if it is
+func checkText(text string, multiline bool) string {
+	if !utf8.ValidString(text) {
+		return "is not valid UTF-8"
+	}
+	for _, r := range text {
+		if multiline && (r == '\n' || r == '\r' || r == '\t') {
+			continue
+		}
+		if unicode.IsControl(r) {
+			return fmt.Sprintf("contains the control character %U", r)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package stake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	claim := newStreamClaim()
+	claim.Title = strings.Repeat("é", 150)
+	claim.Description = "line one\nline two"
+	claim.Tags = []string{"art", "music", "photography", "travel", "food", "nature"}
+	helper := &StakeHelper{Claim: claim}
+
+	if problems := helper.Lint("a-name", ProfileOdysee); len(problems) != 0 {
+		t.Errorf("expected the claim to fit odysee, got %v", problems)
+	}
+
+	problems := helper.Lint(strings.Repeat("n", 65), ProfileSpeech)
+	fields := make([]string, len(problems))
+	for i, problem := range problems {
+		fields[i] = problem.Field
+	}
+	if strings.Join(fields, ",") != "name,title,tags" {
+		t.Errorf("expected problems with the name, title and tags, got %v", problems)
+	}
+
+	if err := helper.Validate("", ProfileMinimal); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLint_StrictUTF8(t *testing.T) {
+	claim := newStreamClaim()
+	claim.Title = "bad \x00 title"
+	claim.Tags = []string{"ok", "\xff"}
+	helper := &StakeHelper{Claim: claim}
+
+	problems := helper.Lint("", ProfileMinimal)
+	if len(problems) != 2 || problems[0].Field != "title" || problems[1].Field != "tags[1]" {
+		t.Errorf("unexpected problems %v", problems)
+	}
+	if err := helper.Validate("", ProfileMinimal); err == nil {
+		t.Error("expected a validation error")
+	}
+
+	helper.Claim.Title, helper.Claim.Tags = "", nil
+	if problems := helper.Lint("", Profile{Name: "loose"}); len(problems) != 0 {
+		t.Errorf("a profile without StrictUTF8 should not check characters, got %v", problems)
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	profile, err := LookupProfile("spee.ch")
+	if err != nil || profile.MaxTags != ProfileSpeech.MaxTags {
+		t.Errorf("expected the spee.ch profile, got %+v, %v", profile, err)
+	}
+	if _, err := LookupProfile("myspace"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
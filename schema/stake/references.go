@@ -0,0 +1,89 @@
+package stake
+
+import (
+	"encoding/hex"
+
+	pb "github.com/lbryio/types/v2/go"
+)
+
+type ReferenceType string
+
+const (
+	// ReferenceSigningChannel points from a signed claim to the channel that signed it
+	ReferenceSigningChannel = ReferenceType("signing_channel")
+	// ReferenceRepost points from a repost to the claim it reposts
+	ReferenceRepost = ReferenceType("repost")
+	// ReferenceCollectionMember points from a collection to each of its members
+	ReferenceCollectionMember = ReferenceType("collection_member")
+	// ReferenceFeatured points from a channel to each of its featured claims
+	ReferenceFeatured = ReferenceType("featured")
+)
+
+// Reference is a claim ID that a claim refers to. Position is the index of the reference in its list (collection
+// members and featured claims are ordered) and is 0 for single references.
+type Reference struct {
+	Type     ReferenceType `json:"type"`
+	ClaimID  string        `json:"claim_id"`
+	Position int           `json:"position"`
+}
+
+// Edge is a directed reference from one claim to another
+type Edge struct {
+	From string `json:"from"`
+	Reference
+}
+
+// References returns every claim ID referenced by the claim, in a stable order: the signing channel first, then the
+// repost target, collection members, and featured claims.
+func (c *StakeHelper) References() []Reference {
+	var refs []Reference
+	if isSignedVersion(c.Version) && len(c.ClaimID) > 0 {
+		refs = append(refs, Reference{Type: ReferenceSigningChannel, ClaimID: claimHashToID(c.ClaimID)})
+	}
+	if c.Claim == nil {
+		return refs
+	}
+	if repost := c.Claim.GetRepost(); repost != nil && len(repost.GetClaimHash()) > 0 {
+		refs = append(refs, Reference{Type: ReferenceRepost, ClaimID: claimHashToID(repost.GetClaimHash())})
+	}
+	refs = append(refs, listReferences(ReferenceCollectionMember, c.Claim.GetCollection())...)
+	refs = append(refs, listReferences(ReferenceFeatured, c.Claim.GetChannel().GetFeatured())...)
+	return refs
+}
+
+// Edges returns the references of the claim with the given ID as a list of edges
+func (c *StakeHelper) Edges(claimID string) []Edge {
+	refs := c.References()
+	edges := make([]Edge, len(refs))
+	for i, ref := range refs {
+		edges[i] = Edge{From: claimID, Reference: ref}
+	}
+	return edges
+}
+
+// DanglingReferences returns the edges that point to a claim ID for which exists returns false
+func DanglingReferences(edges []Edge, exists func(claimID string) bool) []Edge {
+	var dangling []Edge
+	for _, e := range edges {
+		if !exists(e.ClaimID) {
+			dangling = append(dangling, e)
+		}
+	}
+	return dangling
+}
+
+func listReferences(refType ReferenceType, list *pb.ClaimList) []Reference {
+	var refs []Reference
+	for i, ref := range list.GetClaimReferences() {
+		if len(ref.GetClaimHash()) == 0 {
+			continue
+		}
+		refs = append(refs, Reference{Type: refType, ClaimID: claimHashToID(ref.GetClaimHash()), Position: i})
+	}
+	return refs
+}
+
+// claimHashToID converts the little-endian claim hash stored in claims to the hex claim ID used everywhere else
+func claimHashToID(hash []byte) string {
+	return hex.EncodeToString(reverseBytes(hash))
+}
@@ -0,0 +1,71 @@
+package stake
+
+import (
+	"encoding/hex"
+	"testing"
+
+	pb "github.com/lbryio/types/v2/go"
+
+	"gotest.tools/assert"
+)
+
+func claimIDToHash(t *testing.T, claimID string) []byte {
+	b, err := hex.DecodeString(claimID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reverseBytes(b)
+}
+
+func TestReferences(t *testing.T) {
+	channelID := "cf3f7c898af87cc69b06a6ac7899efb9a4878fdb"
+	memberA := "589bc4845caca70977332025990b2a1807732b44"
+	memberB := "60d7ddcc211c381bad63b73415c2065b219258f2"
+
+	collection := &StakeHelper{
+		Claim: &pb.Claim{Type: &pb.Claim_Collection{Collection: &pb.ClaimList{
+			ClaimReferences: []*pb.ClaimReference{
+				{ClaimHash: claimIDToHash(t, memberA)},
+				{ClaimHash: claimIDToHash(t, memberB)},
+			},
+		}}},
+		ClaimID: claimIDToHash(t, channelID),
+		Version: WithSig,
+	}
+
+	refs := collection.References()
+	assert.Equal(t, len(refs), 3)
+	assert.Equal(t, refs[0], Reference{Type: ReferenceSigningChannel, ClaimID: channelID})
+	assert.Equal(t, refs[1], Reference{Type: ReferenceCollectionMember, ClaimID: memberA, Position: 0})
+	assert.Equal(t, refs[2], Reference{Type: ReferenceCollectionMember, ClaimID: memberB, Position: 1})
+
+	repost := &StakeHelper{
+		Claim:   &pb.Claim{Type: &pb.Claim_Repost{Repost: &pb.ClaimReference{ClaimHash: claimIDToHash(t, memberA)}}},
+		Version: NoSig,
+	}
+	refs = repost.References()
+	assert.Equal(t, len(refs), 1)
+	assert.Equal(t, refs[0], Reference{Type: ReferenceRepost, ClaimID: memberA})
+}
+
+func TestDanglingReferences(t *testing.T) {
+	channelID := "cf3f7c898af87cc69b06a6ac7899efb9a4878fdb"
+	featured := "015a97bef520a8b121baec02f9fd36a9f7e8a17e"
+	channel := &StakeHelper{Claim: newChannelClaim(), Version: NoSig}
+	channel.Claim.GetChannel().Featured = &pb.ClaimList{
+		ClaimReferences: []*pb.ClaimReference{{ClaimHash: claimIDToHash(t, featured)}},
+	}
+
+	edges := channel.Edges(channelID)
+	assert.Equal(t, len(edges), 1)
+	assert.Equal(t, edges[0].From, channelID)
+	assert.Equal(t, edges[0].Type, ReferenceFeatured)
+
+	known := map[string]bool{channelID: true}
+	dangling := DanglingReferences(edges, func(claimID string) bool { return known[claimID] })
+	assert.Equal(t, len(dangling), 1)
+	assert.Equal(t, dangling[0].ClaimID, featured)
+
+	known[featured] = true
+	assert.Equal(t, len(DanglingReferences(edges, func(claimID string) bool { return known[claimID] })), 0)
+}
@@ -103,3 +103,16 @@ func (c *StakeHelper) serializedNoSignature() ([]byte, error) {
 		return proto.Marshal(clone)
 	}
 }
+
+// UnsignedValue returns the claim value as it would be without its signature
+func (c *StakeHelper) UnsignedValue() ([]byte, error) {
+	payload, err := c.serializedNoSignature()
+	if err != nil {
+		return nil, err
+	}
+	if c.LegacyClaim != nil {
+		// legacy values have no version byte
+		return payload, nil
+	}
+	return append([]byte{NoSig.byte()}, payload...), nil
+}
@@ -1,7 +1,6 @@
 package stake
 
 import (
-	"crypto/sha256"
 	"encoding/hex"
 
 	"github.com/lbryio/lbry.go/v2/extras/errors"
@@ -34,14 +33,9 @@ func (c *StakeHelper) sign(privKey btcec.PrivateKey, channel StakeHelper, firstI
 		return nil, errors.Err(err)
 	}
 
-	var digest []byte
-	digest = append(digest, txidBytes...)
-	digest = append(digest, c.ClaimID...)
-	digest = append(digest, metadataBytes...)
-	hash := sha256.Sum256(digest)
-	hashBytes := make([]byte, len(hash))
-	for i, b := range hash {
-		hashBytes[i] = b
+	hashBytes, err := c.getClaimSignatureDigest(txidBytes, c.ClaimID, metadataBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	sig, err := privKey.Sign(hashBytes)
@@ -64,21 +58,14 @@ func (c *StakeHelper) signV1(privKey btcec.PrivateKey, channel StakeHelper, clai
 		return nil, errors.Prefix("V1 signing requires claim address and the decode failed with: ", err)
 	}
 
-	var digest []byte
-
 	address := make([]byte, len(addressBytes))
 	for i, b := range addressBytes {
 		address[i] = b
 	}
 
-	digest = append(digest, address...)
-	digest = append(digest, metadataBytes...)
-	digest = append(digest, channel.ClaimID...)
-
-	hash := sha256.Sum256(digest)
-	hashBytes := make([]byte, len(hash))
-	for i, b := range hash {
-		hashBytes[i] = b
+	hashBytes, err := c.getClaimSignatureDigest(address, metadataBytes, channel.ClaimID)
+	if err != nil {
+		return nil, err
 	}
 
 	sig, err := privKey.Sign(hashBytes)
@@ -45,7 +45,7 @@ func (c *StakeHelper) ValidateAddresses(blockchainName string) error {
 		if c.Claim.GetStream() != nil {
 			fee := c.GetStream().GetFee()
 			if fee != nil {
-				return validateAddress(fee.GetAddress(), blockchainName)
+				return errors.Prefix("stream.fee.address", validateAddress(fee.GetAddress(), blockchainName))
 			} else {
 				return nil
 			}
@@ -74,10 +74,9 @@ func validateAddress(tmp_addr []byte, blockchainName string) error {
 }
 
 func getVersionFromByte(versionByte byte) version {
-	if versionByte == byte(0) {
-		return NoSig
-	} else if versionByte == byte(1) {
-		return WithSig
+	v := version(versionByte)
+	if v == NoSig || isSignedVersion(v) {
+		return v
 	}
 
 	return UNKNOWN
@@ -89,13 +88,13 @@ func (c *StakeHelper) ValidateCertificate() error {
 	}
 	_, err := c.GetPublicKey()
 	if err != nil {
-		return errors.Err(err)
+		return errors.Prefix("channel.public_key", err)
 	}
 	return nil
 }
 
 func (c *StakeHelper) IsClaim() bool {
-	return c.Claim != nil && c.Claim.String() != ""
+	return c.Claim != nil && proto.Size(c.Claim) > 0
 }
 
 func (c *StakeHelper) IsSupport() bool {
@@ -111,9 +110,15 @@ func (c *StakeHelper) LoadSupportFromBytes(raw_claim []byte, blockchainName stri
 }
 
 func (c *StakeHelper) loadFromBytes(raw_claim []byte, isSupport bool, blockchainName string) error {
-	if c.Claim.String() != "" && !isSupport {
+	if c.IsClaim() && !isSupport {
 		return errors.Err("already initialized")
 	}
+	return c.load(raw_claim, isSupport, nil, nil)
+}
+
+// load decodes the value into c. A claim is unmarshaled into claimScratch and a legacy claim into legacyScratch when
+// they are not nil, so a Decoder can supply its own messages; c only points to them if they were used.
+func (c *StakeHelper) load(raw_claim []byte, isSupport bool, claimScratch *pb.Claim, legacyScratch *legacy_pb.Claim) error {
 	if len(raw_claim) < 1 {
 		return errors.Err("there is nothing to decode")
 	}
@@ -126,7 +131,7 @@ func (c *StakeHelper) loadFromBytes(raw_claim []byte, isSupport bool, blockchain
 	pbPayload := raw_claim[1:]
 	var claimID []byte
 	var signature []byte
-	if version == WithSig {
+	if isSignedVersion(version) {
 		if len(raw_claim) < 85 {
 			return errors.Err("signature version indicated by 1st byte but not enough bytes for valid format")
 		}
@@ -137,7 +142,10 @@ func (c *StakeHelper) loadFromBytes(raw_claim []byte, isSupport bool, blockchain
 
 	var err error
 	if !isSupport {
-		claim_pb = &pb.Claim{}
+		claim_pb = claimScratch
+		if claim_pb == nil {
+			claim_pb = &pb.Claim{}
+		}
 		err = proto.Unmarshal(pbPayload, claim_pb)
 	} else {
 		support := &pb.Support{}
@@ -147,7 +155,10 @@ func (c *StakeHelper) loadFromBytes(raw_claim []byte, isSupport bool, blockchain
 		}
 	}
 	if err != nil {
-		legacy_claim_pb = &legacy_pb.Claim{}
+		legacy_claim_pb = legacyScratch
+		if legacy_claim_pb == nil {
+			legacy_claim_pb = &legacy_pb.Claim{}
+		}
 		legacyErr := proto.Unmarshal(raw_claim, legacy_claim_pb)
 		if legacyErr == nil {
 			claim_pb, err = migrateV1PBClaim(*legacy_claim_pb)
@@ -163,7 +174,7 @@ func (c *StakeHelper) loadFromBytes(raw_claim []byte, isSupport bool, blockchain
 				version = NoSig
 			}
 		} else {
-			return err
+			return errors.Err(c.describeDecodeError(raw_claim, pbPayload, isSupport, err))
 		}
 	}
 
@@ -191,6 +202,19 @@ func (c *StakeHelper) loadFromBytes(raw_claim []byte, isSupport bool, blockchain
 	return nil
 }
 
+// describeDecodeError finds the field that made a value fail to decode. Values that don't start with a version byte
+// are taken to be legacy claims.
+func (c *StakeHelper) describeDecodeError(raw, pbPayload []byte, isSupport bool, err error) error {
+	offset := len(raw) - len(pbPayload)
+	switch {
+	case isSupport:
+		return describeDecodeError(pbPayload, offset, &pb.Support{}, err)
+	case getVersionFromByte(raw[0]) != UNKNOWN:
+		return describeDecodeError(pbPayload, offset, &pb.Claim{}, err)
+	}
+	return describeDecodeError(raw, 0, &legacy_pb.Claim{}, err)
+}
+
 func (c *StakeHelper) LoadFromHexString(claim_hex string, blockchainName string) error {
 	buf, err := hex.DecodeString(claim_hex)
 	if err != nil {
@@ -208,7 +232,7 @@ func (c *StakeHelper) LoadSupportFromHexString(claim_hex string, blockchainName
 }
 
 func DecodeClaimProtoBytes(serialized []byte, blockchainName string) (*StakeHelper, error) {
-	claim := &StakeHelper{&pb.Claim{}, &pb.Support{}, nil, nil, NoSig, nil, nil}
+	claim := &StakeHelper{&pb.Claim{}, nil, nil, nil, NoSig, nil, nil}
 	err := claim.LoadFromBytes(serialized, blockchainName)
 	if err != nil {
 		return nil, err
@@ -239,10 +263,25 @@ func DecodeClaimBytes(serialized []byte, blockchainName string) (*StakeHelper, e
 	if err == nil {
 		return helper, nil
 	}
-	helper = &StakeHelper{}
 	//If protobuf fails, try json versions before returning an error.
+	return decodeJSONClaimOr(serialized, err)
+}
+
+// decodeJSONClaimOr decodes a json claim value. If the value is not json at all, protoErr is returned instead of the
+// json error, since it says more about what is wrong.
+func decodeJSONClaimOr(serialized []byte, protoErr error) (*StakeHelper, error) {
+	helper, err := decodeJSONClaim(serialized)
+	if err != nil && (len(serialized) == 0 || serialized[0] != '{') {
+		return nil, protoErr
+	}
+	return helper, err
+}
+
+// decodeJSONClaim migrates a json v1, v2 or v3 claim value
+func decodeJSONClaim(serialized []byte) (*StakeHelper, error) {
+	helper := &StakeHelper{}
 	v1Claim := new(V1Claim)
-	err = v1Claim.Unmarshal(serialized)
+	err := v1Claim.Unmarshal(serialized)
 	if err != nil {
 		v2Claim := new(V2Claim)
 		err := v2Claim.Unmarshal(serialized)
@@ -295,7 +334,7 @@ func (c *StakeHelper) CompileValue() ([]byte, error) {
 	}
 	var value []byte
 	value = append(value, c.Version.byte())
-	if c.Version == WithSig {
+	if isSignedVersion(c.Version) {
 		value = append(value, c.ClaimID...)
 		value = append(value, c.Signature...)
 	}
@@ -2,31 +2,63 @@ package stake
 
 import (
 	"crypto/ecdsa"
-	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"math/big"
 
 	"github.com/lbryio/lbry.go/v2/extras/errors"
 	"github.com/lbryio/lbry.go/v2/schema/address"
+
+	"github.com/btcsuite/btcd/btcec"
 )
 
 const SECP256k1 = "SECP256k1"
 
+// VerifyOptions changes how signatures are verified. The zero value is the strictest.
+type VerifyOptions struct {
+	// AllowHighS accepts signatures whose S value is in the upper half of the curve order. For every valid signature
+	// (R, S), (R, N-S) is valid too, so accepting both lets anyone derive a second valid signature for a claim without
+	// the key. Only signatures with the lower S are accepted unless this is set, e.g. by an indexer that has to verify
+	// claims signed before signers normalized S.
+	AllowHighS bool
+}
+
+// signatureLength is the length of a raw R || S signature
+const signatureLength = 64
+
+var halfOrder = new(big.Int).Rsh(btcec.S256().N, 1)
+
 //const NIST256p = "NIST256p"
 //const NIST384p = "NIST384p"
 
-func getClaimSignatureDigest(bytes ...[]byte) [32]byte {
+// getClaimSignatureDigest hashes the signed parts of a claim with the digest registered for the claim's version
+func (c *StakeHelper) getClaimSignatureDigest(bytes ...[]byte) ([]byte, error) {
+	digest, err := SignatureDigest(c.signatureVersion())
+	if err != nil {
+		return nil, err
+	}
+	return digest(bytes...), nil
+}
 
-	var combined []byte
-	for _, b := range bytes {
-		combined = append(combined, b...)
+// signatureVersion is the version whose digest is used to sign or verify the claim. Claims that have not been marked
+// as signed yet are signed with the default WithSig digest.
+func (c *StakeHelper) signatureVersion() version {
+	if c.Version == NoSig {
+		return WithSig
 	}
-	digest := sha256.Sum256(combined)
-	return [32]byte(digest)
+	return c.Version
 }
 
 func (c *StakeHelper) VerifyDigest(certificate *StakeHelper, signature [64]byte, digest [32]byte) bool {
+	return c.verifyDigest(certificate, signature, digest[:], VerifyOptions{})
+}
+
+// VerifyDigestOpts is VerifyDigest with options
+func (c *StakeHelper) VerifyDigestOpts(certificate *StakeHelper, signature [64]byte, digest [32]byte, opts VerifyOptions) bool {
+	return c.verifyDigest(certificate, signature, digest[:], opts)
+}
+
+func (c *StakeHelper) verifyDigest(certificate *StakeHelper, signature [64]byte, digest []byte, opts VerifyOptions) bool {
 	if certificate == nil {
 		return false
 	}
@@ -35,22 +67,30 @@ func (c *StakeHelper) VerifyDigest(certificate *StakeHelper, signature [64]byte,
 	S := &big.Int{}
 	R.SetBytes(signature[0:32])
 	S.SetBytes(signature[32:64])
+	if !opts.AllowHighS && S.Cmp(halfOrder) > 0 {
+		return false
+	}
 	pk, err := certificate.GetPublicKey()
 	if err != nil {
 		return false
 	}
-	return ecdsa.Verify(pk.ToECDSA(), digest[:], R, S)
+	return ecdsa.Verify(pk.ToECDSA(), digest, R, S)
 }
 
 func (c *StakeHelper) ValidateClaimSignature(certificate *StakeHelper, k string, certificateId string, blockchainName string) (bool, error) {
+	return c.ValidateClaimSignatureOpts(certificate, k, certificateId, blockchainName, VerifyOptions{})
+}
+
+// ValidateClaimSignatureOpts is ValidateClaimSignature with options, which apply to this call only
+func (c *StakeHelper) ValidateClaimSignatureOpts(certificate *StakeHelper, k string, certificateId string, blockchainName string, opts VerifyOptions) (bool, error) {
 	if c.LegacyClaim != nil {
-		return c.validateV1ClaimSignature(certificate, k, certificateId, blockchainName)
+		return c.validateV1ClaimSignature(certificate, k, certificateId, blockchainName, opts)
 	}
 
-	return c.validateClaimSignature(certificate, k, certificateId, blockchainName)
+	return c.validateClaimSignature(certificate, k, certificateId, blockchainName, opts)
 }
 
-func (c *StakeHelper) validateClaimSignature(certificate *StakeHelper, firstInputTxHash, certificateId string, blockchainName string) (bool, error) {
+func (c *StakeHelper) validateClaimSignature(certificate *StakeHelper, firstInputTxHash, certificateId string, blockchainName string, opts VerifyOptions) (bool, error) {
 	certificateIdSlice, err := hex.DecodeString(certificateId)
 	if err != nil {
 		return false, errors.Err(err)
@@ -61,20 +101,19 @@ func (c *StakeHelper) validateClaimSignature(certificate *StakeHelper, firstInpu
 		return false, errors.Err(err)
 	}
 
-	signature := c.Signature
-	if signature == nil {
-		return false, errors.Err("claim does not have a signature")
-	}
-	signatureBytes := [64]byte{}
-	for i, b := range signature {
-		signatureBytes[i] = b
+	signatureBytes, err := c.rawSignature()
+	if err != nil {
+		return false, err
 	}
 
-	claimDigest := getClaimSignatureDigest(firstInputTxIDBytes, certificateIdSlice, c.Payload)
-	return c.VerifyDigest(certificate, signatureBytes, claimDigest), nil
+	claimDigest, err := c.getClaimSignatureDigest(firstInputTxIDBytes, certificateIdSlice, c.Payload)
+	if err != nil {
+		return false, err
+	}
+	return c.verifyDigest(certificate, signatureBytes, claimDigest, opts), nil
 }
 
-func (c *StakeHelper) validateV1ClaimSignature(certificate *StakeHelper, claimAddy string, certificateId string, blockchainName string) (bool, error) {
+func (c *StakeHelper) validateV1ClaimSignature(certificate *StakeHelper, claimAddy string, certificateId string, blockchainName string, opts VerifyOptions) (bool, error) {
 	addressBytes, err := address.DecodeAddress(claimAddy, blockchainName)
 	if err != nil {
 		return false, err
@@ -86,13 +125,9 @@ func (c *StakeHelper) validateV1ClaimSignature(certificate *StakeHelper, claimAd
 		return false, err
 	}
 
-	signature := c.Signature
-	if signature == nil {
-		return false, errors.Err("claim does not have a signature")
-	}
-	signatureBytes := [64]byte{}
-	for i := range signatureBytes {
-		signatureBytes[i] = signature[i]
+	signatureBytes, err := c.rawSignature()
+	if err != nil {
+		return false, err
 	}
 
 	claimAddress, err := address.ValidateAddress(addressBytes, blockchainName)
@@ -105,8 +140,24 @@ func (c *StakeHelper) validateV1ClaimSignature(certificate *StakeHelper, claimAd
 		return false, errors.Err("serialization error")
 	}
 
-	claimDigest := getClaimSignatureDigest(claimAddress[:], serializedNoSig, certificateIdSlice)
-	return c.VerifyDigest(certificate, signatureBytes, claimDigest), nil
+	claimDigest, err := c.getClaimSignatureDigest(claimAddress[:], serializedNoSig, certificateIdSlice)
+	if err != nil {
+		return false, err
+	}
+	return c.verifyDigest(certificate, signatureBytes, claimDigest, opts), nil
+}
+
+// rawSignature returns the claim's signature, which must be exactly 64 bytes
+func (c *StakeHelper) rawSignature() ([signatureLength]byte, error) {
+	var signature [signatureLength]byte
+	if c.Signature == nil {
+		return signature, errors.Err("claim does not have a signature")
+	}
+	if len(c.Signature) != signatureLength {
+		return signature, errors.Err("signature is %d bytes long, expected %d", len(c.Signature), signatureLength)
+	}
+	copy(signature[:], c.Signature)
+	return signature, nil
 }
 
 func GetOutpointHash(txid string, vout uint32) (string, error) {
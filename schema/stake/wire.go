@@ -0,0 +1,297 @@
+package stake
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// DecodeError tells which field of a claim value could not be decoded, and where in the value it is
+type DecodeError struct {
+	// Path is the protobuf path of the field, e.g. "stream.fee.amount" or "tags[2]". It is empty if the problem is not
+	// in a known field.
+	Path string
+	// Offset is the position in the value where the field's data starts
+	Offset  int
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s at byte %d", e.Message, e.Offset)
+	}
+	return fmt.Sprintf("%s: %s at byte %d", e.Path, e.Message, e.Offset)
+}
+
+// describeDecodeError walks the wire format of a protobuf value that failed to unmarshal into msg, and returns a
+// DecodeError for the first problem it finds. offset is where data starts in the claim value. If the walk finds
+// nothing wrong, the original error is returned.
+func describeDecodeError(data []byte, offset int, msg proto.Message, original error) error {
+	w := wireWalker{offset: offset}
+	if !w.message(data, reflect.TypeOf(msg).Elem(), "") {
+		return w.err
+	}
+	return original
+}
+
+// wireField is what the walker needs to know about a field of a generated protobuf message
+type wireField struct {
+	name     string
+	wireType int
+	repeated bool
+	utf8     bool
+	// message is the type of a nested message, or nil for scalars
+	message reflect.Type
+}
+
+var wireFieldCache sync.Map // reflect.Type -> map[uint64]wireField
+
+// wireFields reads the fields of a generated message from its struct tags, including the fields of its oneofs
+func wireFields(t reflect.Type) map[uint64]wireField {
+	if fields, ok := wireFieldCache.Load(t); ok {
+		return fields.(map[uint64]wireField)
+	}
+	fields := make(map[uint64]wireField)
+	addField := func(f reflect.StructField) {
+		tag := strings.Split(f.Tag.Get("protobuf"), ",")
+		if len(tag) < 3 {
+			return
+		}
+		number, err := strconv.ParseUint(tag[1], 10, 64)
+		if err != nil {
+			return
+		}
+		field := wireField{repeated: tag[2] == "rep"}
+		switch tag[0] {
+		case "varint", "zigzag32", "zigzag64":
+			field.wireType = wireVarint
+		case "fixed64":
+			field.wireType = wireFixed64
+		case "fixed32":
+			field.wireType = wireFixed32
+		default:
+			field.wireType = wireBytes
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8 {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct {
+			field.message = ft.Elem()
+		}
+		for _, option := range tag[3:] {
+			if strings.HasPrefix(option, "name=") {
+				field.name = strings.TrimPrefix(option, "name=")
+			}
+			if option == "proto3" {
+				// proto3 strings must be valid UTF-8
+				field.utf8 = ft.Kind() == reflect.String
+			}
+		}
+		fields[number] = field
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		addField(t.Field(i))
+	}
+	if oneofs, ok := reflect.New(t).Interface().(interface{ XXX_OneofWrappers() []interface{} }); ok {
+		for _, wrapper := range oneofs.XXX_OneofWrappers() {
+			wt := reflect.TypeOf(wrapper).Elem()
+			for i := 0; i < wt.NumField(); i++ {
+				addField(wt.Field(i))
+			}
+		}
+	}
+	wireFieldCache.Store(t, fields)
+	return fields
+}
+
+const (
+	wireVarint     = 0
+	wireFixed64    = 1
+	wireBytes      = 2
+	wireStartGroup = 3
+	wireEndGroup   = 4
+	wireFixed32    = 5
+)
+
+type wireWalker struct {
+	offset int
+	err    *DecodeError
+}
+
+func (w *wireWalker) fail(path string, at int, format string, a ...interface{}) bool {
+	w.err = &DecodeError{Path: path, Offset: w.offset + at, Message: fmt.Sprintf(format, a...)}
+	return false
+}
+
+// message walks the fields of a message of type t in data. It returns false and sets w.err at the first problem.
+func (w *wireWalker) message(data []byte, t reflect.Type, path string) bool {
+	fields := wireFields(t)
+	counts := make(map[uint64]int)
+	for i := 0; i < len(data); {
+		start := i
+		key, n := readVarint(data[i:])
+		if n <= 0 {
+			return w.fail(path, start, "field key %s", varintProblem(n))
+		}
+		i += n
+		number, wireType := key>>3, int(key&7)
+		if number == 0 {
+			return w.fail(path, start, "field number 0 is not allowed")
+		}
+
+		field, known := fields[number]
+		fieldPath := "field " + strconv.FormatUint(number, 10)
+		if known {
+			fieldPath = field.name
+			if field.repeated {
+				fieldPath += "[" + strconv.Itoa(counts[number]) + "]"
+				counts[number]++
+			}
+		}
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		switch wireType {
+		case wireVarint:
+			_, n := readVarint(data[i:])
+			if n <= 0 {
+				return w.fail(fieldPath, i, "value %s", varintProblem(n))
+			}
+			i += n
+		case wireFixed64:
+			if len(data)-i < 8 {
+				return w.fail(fieldPath, i, "needs 8 bytes, only %d left", len(data)-i)
+			}
+			i += 8
+		case wireFixed32:
+			if len(data)-i < 4 {
+				return w.fail(fieldPath, i, "needs 4 bytes, only %d left", len(data)-i)
+			}
+			i += 4
+		case wireBytes:
+			length, n := readVarint(data[i:])
+			if n <= 0 {
+				return w.fail(fieldPath, i, "length %s", varintProblem(n))
+			}
+			i += n
+			if length > uint64(len(data)-i) {
+				return w.fail(fieldPath, i, "length %d is more than the %d bytes left", length, len(data)-i)
+			}
+			value := data[i : i+int(length)]
+			if known && field.wireType == wireBytes {
+				if field.message != nil {
+					inner := wireWalker{offset: w.offset + i}
+					if !inner.message(value, field.message, fieldPath) {
+						w.err = inner.err
+						return false
+					}
+				} else if field.utf8 && !utf8.Valid(value) {
+					return w.fail(fieldPath, i, "string is not valid UTF-8")
+				}
+			} else if known && field.wireType == wireVarint && field.repeated {
+				// packed repeated varints
+				for j := 0; j < len(value); {
+					_, n := readVarint(value[j:])
+					if n <= 0 {
+						return w.fail(fieldPath, i+j, "packed value %s", varintProblem(n))
+					}
+					j += n
+				}
+			}
+			i += int(length)
+		case wireStartGroup:
+			end, ok := w.skipGroup(data, i, number, fieldPath)
+			if !ok {
+				return false
+			}
+			i = end
+		case wireEndGroup:
+			return w.fail(fieldPath, start, "group ends without starting")
+		default:
+			return w.fail(fieldPath, start, "unknown wire type %d", wireType)
+		}
+	}
+	return true
+}
+
+// skipGroup skips the fields of a deprecated group up to its end marker, and returns the position after it
+func (w *wireWalker) skipGroup(data []byte, i int, number uint64, path string) (int, bool) {
+	for i < len(data) {
+		start := i
+		key, n := readVarint(data[i:])
+		if n <= 0 {
+			return 0, w.fail(path, start, "field key %s", varintProblem(n))
+		}
+		i += n
+		switch int(key & 7) {
+		case wireVarint:
+			_, n = readVarint(data[i:])
+			if n <= 0 {
+				return 0, w.fail(path, i, "value %s", varintProblem(n))
+			}
+			i += n
+		case wireFixed64:
+			i += 8
+		case wireFixed32:
+			i += 4
+		case wireBytes:
+			length, n := readVarint(data[i:])
+			if n <= 0 {
+				return 0, w.fail(path, i, "length %s", varintProblem(n))
+			}
+			i += n
+			if length > uint64(len(data)-i) {
+				return 0, w.fail(path, i, "length %d is more than the %d bytes left", length, len(data)-i)
+			}
+			i += int(length)
+		case wireStartGroup:
+			end, ok := w.skipGroup(data, i, key>>3, path)
+			if !ok {
+				return 0, false
+			}
+			i = end
+		case wireEndGroup:
+			if key>>3 != number {
+				return 0, w.fail(path, start, "group %d ends with the marker of group %d", number, key>>3)
+			}
+			return i, true
+		default:
+			return 0, w.fail(path, start, "unknown wire type %d", key&7)
+		}
+		if i > len(data) {
+			return 0, w.fail(path, start, "value runs past the end of the data")
+		}
+	}
+	return 0, w.fail(path, i, "group %d does not end", number)
+}
+
+// readVarint returns the value and length of the varint at the start of b. The length is 0 if b ends first, and -1 if
+// the value doesn't fit in 64 bits.
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b); i++ {
+		if i == 10 || (i == 9 && b[i] > 1) {
+			return 0, -1
+		}
+		v |= uint64(b[i]&0x7f) << (7 * uint(i))
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func varintProblem(n int) string {
+	if n < 0 {
+		return "exceeds uint64"
+	}
+	return "is cut off"
+}
@@ -0,0 +1,76 @@
+package stake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbry.go/v2/extras/errors"
+)
+
+func TestDecodeError(t *testing.T) {
+	overflow := []byte{0x18, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02} // fee amount
+	stream := append([]byte{0x32, byte(len(overflow))}, overflow...)                     // stream fee
+	tests := []struct {
+		name     string
+		value    []byte
+		expected DecodeError
+	}{
+		{
+			name:     "varint overflow",
+			value:    append([]byte{0x00, 0x0a, byte(len(stream))}, stream...),
+			expected: DecodeError{Path: "stream.fee.amount", Offset: 6, Message: "value exceeds uint64"},
+		},
+		{
+			name:     "invalid utf-8",
+			value:    []byte{0x00, 0x5a, 0x01, 'a', 0x5a, 0x02, 0xff, 0xfe},
+			expected: DecodeError{Path: "tags[1]", Offset: 6, Message: "string is not valid UTF-8"},
+		},
+		{
+			name:     "length past the end",
+			value:    []byte{0x00, 0x42, 0x10, 'a'},
+			expected: DecodeError{Path: "title", Offset: 3, Message: "length 16 is more than the 1 bytes left"},
+		},
+		{
+			name:     "legacy claim",
+			value:    []byte{0x08, 0x01, 0x10, 0x02, 0x1a, 0x7f, 0x01},
+			expected: DecodeError{Path: "stream", Offset: 6, Message: "length 127 is more than the 1 bytes left"},
+		},
+		{
+			name:     "unknown wire type",
+			value:    []byte{0x00, 0x42, 0x01, 'a', 0xa7, 0x01},
+			expected: DecodeError{Path: "field 20", Offset: 4, Message: "unknown wire type 7"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeClaimBytes(test.value, "lbrycrd_main")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			decodeErr, ok := errors.Unwrap(err).(*DecodeError)
+			if !ok {
+				t.Fatalf("expected a DecodeError, got %T: %v", errors.Unwrap(err), err)
+			}
+			if *decodeErr != test.expected {
+				t.Errorf("expected %q, got %q", test.expected.Error(), decodeErr.Error())
+			}
+		})
+	}
+}
+
+func TestDecodeError_Message(t *testing.T) {
+	err := &DecodeError{Path: "stream.fee.amount", Offset: 37, Message: "value exceeds uint64"}
+	if err.Error() != "stream.fee.amount: value exceeds uint64 at byte 37" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestValidateCertificate_FieldPath(t *testing.T) {
+	claim := &StakeHelper{Claim: newChannelClaim()}
+	claim.Claim.GetChannel().PublicKey = []byte{0x30, 0x00}
+	err := claim.ValidateCertificate()
+	if err == nil || !strings.HasPrefix(err.Error(), "channel.public_key: ") {
+		t.Errorf("expected an error for channel.public_key, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+//go:build js && wasm
+// +build js,wasm
+
+// lbryschema-wasm exposes claim decoding, claim IDs and signature checks to JavaScript, so web tools inspect claims with
+// the same code the Go services use. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o lbryschema.wasm ./wasm
+//
+// and load it with the wasm_exec.js that ships with Go. Every function returns an object with either a value or an
+// error field.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/lbryio/lbry.go/v2/schema/stake"
+)
+
+func main() {
+	js.Global().Set("lbryschema", js.ValueOf(map[string]interface{}{
+		"decodeClaim":    js.FuncOf(decodeClaim),
+		"claimID":        js.FuncOf(claimID),
+		"verifySignedBy": js.FuncOf(verifySignedBy),
+	}))
+	select {}
+}
+
+func result(value interface{}, err error) interface{} {
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	return map[string]interface{}{"value": value}
+}
+
+func argError(usage string) interface{} {
+	return map[string]interface{}{"error": "usage: " + usage}
+}
+
+// decodeClaim(claimHex, blockchainName) returns the claim as a JSON string
+func decodeClaim(this js.Value, args []js.Value) interface{} {
+	if len(args) != 2 {
+		return argError("decodeClaim(claimHex, blockchainName)")
+	}
+	claim, err := stake.DecodeClaimHex(args[0].String(), args[1].String())
+	if err != nil {
+		return result(nil, err)
+	}
+	return result(claim.RenderJSON())
+}
+
+// claimID(txid, nout) returns the ID of the claim created by the output
+func claimID(this js.Value, args []js.Value) interface{} {
+	if len(args) != 2 {
+		return argError("claimID(txid, nout)")
+	}
+	return result(stake.ClaimIDFromOutpoint(args[0].String(), args[1].Int()))
+}
+
+// verifySignedBy(claimHex, channelHex, firstInputTxID, channelClaimID, blockchainName) returns true if the claim carries
+// a valid signature from the channel
+func verifySignedBy(this js.Value, args []js.Value) interface{} {
+	if len(args) != 5 {
+		return argError("verifySignedBy(claimHex, channelHex, firstInputTxID, channelClaimID, blockchainName)")
+	}
+	blockchainName := args[4].String()
+	claim, err := stake.DecodeClaimHex(args[0].String(), blockchainName)
+	if err != nil {
+		return result(nil, err)
+	}
+	channel, err := stake.DecodeClaimHex(args[1].String(), blockchainName)
+	if err != nil {
+		return result(nil, err)
+	}
+	return result(claim.ValidateClaimSignature(channel, args[2].String(), args[3].String(), blockchainName))
+}